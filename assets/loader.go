@@ -2,11 +2,21 @@ package assets
 
 import (
 	"bytes"
-	_ "embed"
+	"embed"
+	"errors"
+	"fmt"
 	"image"
 	_ "image/png"
-
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"BRIX/config"
 	"BRIX/entities"
+	"BRIX/themes"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -19,23 +29,11 @@ var paddlePNG []byte
 //go:embed paddles/paddle-silver.png
 var paddleSilverPNG []byte
 
-// NOTE: Currently, the generic brick.png sprite is reused for the new brick variants.
-// Replace these files with real sprites of identical names when available.
-
-//go:embed bricks/brick-standard.png
-var brickStandardPNG []byte
-
-//go:embed bricks/brick-tusi.png
-var brickTusiPNG []byte
-
-//go:embed bricks/brick-weed.png
-var brickWeedPNG []byte
-
-//go:embed bricks/brick-columbia.png
-var brickColumbiaPNG []byte
-
-//go:embed bricks/brick-supreme.png
-var brickSupremePNG []byte
+// Brick sprites are looked up by the file names in config/brick_types.json, and
+// builtin themes pick backgrounds, brick and paddle sprites from the same files
+//
+//go:embed bricks/*.png levels/*.png paddles/*.png
+var sprites embed.FS
 
 // Embed level background
 //
@@ -64,13 +62,15 @@ var gameOverScreenPNG []byte
 //go:embed startscreens/ball-lost-screen.png
 var ballLostScreenPNG []byte
 
+// init gives builtin themes access to the embedded sprites
+func init() {
+	themes.SetBuiltinFiles(sprites)
+}
+
 type Images struct {
 	Paddle          *ebiten.Image
-	BrickStandard   *ebiten.Image
-	BrickColumbia   *ebiten.Image
-	BrickSupreme    *ebiten.Image
-	BrickTusi       *ebiten.Image
-	BrickWeed       *ebiten.Image
+	Bricks          map[entities.BrickType]*ebiten.Image   // by brick type, see GetBrickImage
+	Damage          map[entities.BrickType][]*ebiten.Image // damage states by brick type, see GetDamageImages
 	LevelBackground *ebiten.Image
 	StartScreen1    *ebiten.Image
 	StartScreen2    *ebiten.Image
@@ -79,122 +79,212 @@ type Images struct {
 	LevelCompleteScreen *ebiten.Image
 	GameOverScreen      *ebiten.Image
 	BallLostScreen      *ebiten.Image
+
+	pack *SpritePack // nil when only the default sprites are used
 }
 
-func LoadImages() (*Images, error) {
-	paddle, err := loadImageFromBytes(paddleSilverPNG)
-	if err != nil {
-		// fallback to old paddle.png
-		paddle, err = loadImageFromBytes(paddlePNG)
-		if err != nil {
+// LoadImages decodes the sprites, taking each one the sprite pack at
+// spritePack (a directory or zip) replaces from the pack and the rest from the
+// copies embedded in the binary. An empty spritePack uses only the embedded sprites.
+func LoadImages(spritePack string) (*Images, error) {
+	var pack *SpritePack
+	if spritePack != "" {
+		var err error
+		if pack, err = OpenSpritePack(spritePack); err != nil {
 			return nil, err
 		}
 	}
+	return LoadImagesFrom("", pack)
+}
 
-	brickStandard, err := loadImageFromBytes(brickStandardPNG)
-	if err != nil {
-		return nil, err
+// LoadImagesFrom decodes the sprites, preferring the sprite pack's, then files
+// under dir (laid out like the assets directory, e.g. dir/bricks/brick-weed.png),
+// then the embedded copies. An empty dir and nil pack use only the embedded
+// sprites. Every sprite that fails to decode is reported.
+func LoadImagesFrom(dir string, pack *SpritePack) (*Images, error) {
+	l := loader{dir: dir, pack: pack}
+	imgs := &Images{pack: pack}
+
+	var errs []error
+	var err error
+	if imgs.Paddle, err = l.load("paddle", "paddles/paddle-silver.png", paddleSilverPNG); err != nil {
+		// fallback to old paddle.png
+		if imgs.Paddle, err = l.load("paddle", "paddles/paddle.png", paddlePNG); err != nil {
+			errs = append(errs, err)
+		}
 	}
-
-	brickTusi, err := loadImageFromBytes(brickTusiPNG)
-	if err != nil {
-		return nil, err
+	if imgs.Bricks, err = l.loadBricks(); err != nil {
+		errs = append(errs, err)
 	}
-
-	brickWeed, err := loadImageFromBytes(brickWeedPNG)
-	if err != nil {
-		return nil, err
+	if imgs.Damage, err = l.loadDamage(); err != nil {
+		errs = append(errs, err)
 	}
-
-	brickColumbia, err := loadImageFromBytes(brickColumbiaPNG)
-	if err != nil {
-		return nil, err
+	for _, sprite := range []struct {
+		dst  **ebiten.Image
+		item string
+		path string
+		data []byte
+	}{
+		{&imgs.LevelBackground, "background", "levels/level.png", levelBackgroundPNG},
+
+		// Start screens
+		{&imgs.StartScreen1, "screens.start-1", "startscreens/start-screen-1.png", startScreen1PNG},
+		{&imgs.StartScreen2, "screens.start-2", "startscreens/start-screen-2.png", startScreen2PNG},
+
+		// UI screens
+		{&imgs.PauseScreen, "screens.pause", "startscreens/pause-screen.png", pauseScreenPNG},
+		{&imgs.LevelCompleteScreen, "screens.level-complete", "startscreens/level-complete-screen.png", levelCompleteScreenPNG},
+		{&imgs.GameOverScreen, "screens.game-over", "startscreens/game-over-screen.png", gameOverScreenPNG},
+		{&imgs.BallLostScreen, "screens.ball-lost", "startscreens/ball-lost-screen.png", ballLostScreenPNG},
+	} {
+		if *sprite.dst, err = l.load(sprite.item, sprite.path, sprite.data); err != nil {
+			errs = append(errs, err)
+		}
 	}
-
-	brickSupreme, err := loadImageFromBytes(brickSupremePNG)
-	if err != nil {
-		return nil, err
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
+	return imgs, nil
+}
 
-	levelBackground, err := loadImageFromBytes(levelBackgroundPNG)
-	if err != nil {
-		return nil, err
+// SpritePack returns the sprite pack the images were loaded from, or nil
+func (imgs *Images) SpritePack() *SpritePack {
+	return imgs.pack
+}
+
+// loader reads sprites from an optional sprite pack and directory before falling back to embedded data
+type loader struct {
+	dir  string
+	pack *SpritePack
+}
+
+// exists reports whether the sprite directory has a file
+func (l loader) exists(path string) bool {
+	if l.dir == "" {
+		return false
 	}
+	_, err := os.Stat(filepath.Join(l.dir, filepath.FromSlash(path)))
+	return err == nil
+}
 
-	// Load start screens
-	start1, err := loadImageFromBytes(startScreen1PNG)
-	if err != nil {
-		return nil, err
+// load decodes the sprite for an item of the sprite pack, which the assets
+// directory and embedded copies call path
+func (l loader) load(item, path string, embedded []byte) (*ebiten.Image, error) {
+	if file, ok := l.pack.file(item); ok {
+		return l.pack.decode(item, file)
+	}
+	if l.dir == "" {
+		return DecodeImage(embedded)
+	}
+	file := filepath.Join(l.dir, filepath.FromSlash(path))
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return DecodeImage(embedded)
 	}
-	start2, err := loadImageFromBytes(startScreen2PNG)
 	if err != nil {
 		return nil, err
 	}
-
-	// Load new UI screens
-	pauseScreen, err := loadImageFromBytes(pauseScreenPNG)
+	img, err := DecodeImage(data)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %v", file, err)
 	}
+	return img, nil
+}
 
-	levelCompleteScreen, err := loadImageFromBytes(levelCompleteScreenPNG)
+// DecodeImage decodes a PNG into an image
+func DecodeImage(data []byte) (*ebiten.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
+	return ebiten.NewImageFromImage(img), nil
+}
 
-	gameOverScreen, err := loadImageFromBytes(gameOverScreenPNG)
-	if err != nil {
-		return nil, err
+// GetBrickImage returns a brick type's sprite. Types without one, including
+// types added to the config after the images were loaded, get a block of their colour.
+func (imgs *Images) GetBrickImage(brickType entities.BrickType) *ebiten.Image {
+	if img, ok := imgs.Bricks[brickType]; ok {
+		return img
 	}
+	img := colorBlock(brickType)
+	imgs.Bricks[brickType] = img
+	return img
+}
 
-	ballLostScreen, err := loadImageFromBytes(ballLostScreenPNG)
-	if err != nil {
-		return nil, err
+// loadBricks loads the sprite of every brick type in config.Brick. A type
+// whose sprite doesn't exist is drawn as a block of its colour.
+func (l loader) loadBricks() (map[entities.BrickType]*ebiten.Image, error) {
+	keys := make([]string, 0, len(config.Brick))
+	for key := range config.Brick {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+
+	bricks := make(map[entities.BrickType]*ebiten.Image, len(keys))
+	var errs []error
+	for _, key := range keys {
+		// The sprite pack can give a sprite even to types without one of their own
+		t, item := entities.BrickType(key), "bricks."+key
+		_, inPack := l.pack.file(item)
+		sprite := t.Config().Sprite
+		if sprite == "" && !inPack {
+			bricks[t] = colorBlock(t)
+			continue
+		}
 
-	return &Images{
-		Paddle:          paddle,
-		BrickStandard:   brickStandard,
-		BrickColumbia:   brickColumbia,
-		BrickSupreme:    brickSupreme,
-		BrickTusi:       brickTusi,
-		BrickWeed:       brickWeed,
-		LevelBackground: levelBackground,
-		StartScreen1:    start1,
-		StartScreen2:    start2,
-
-		PauseScreen:         pauseScreen,
-		LevelCompleteScreen: levelCompleteScreen,
-		GameOverScreen:      gameOverScreen,
-		BallLostScreen:      ballLostScreen,
-	}, nil
+		file := path.Join("bricks", sprite)
+		embedded, err := fs.ReadFile(sprites, file)
+		if err != nil && !inPack && !l.exists(file) {
+			log.Printf("Brick type %q: sprite %s not found, drawing it in its colour", key, file)
+			bricks[t] = colorBlock(t)
+			continue
+		}
+		if bricks[t], err = l.load(item, file, embedded); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return bricks, errors.Join(errs...)
 }
 
-func loadImageFromBytes(data []byte) (*ebiten.Image, error) {
-	img, _, err := image.Decode(bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	return ebiten.NewImageFromImage(img), nil
+// GetDamageImages returns a brick type's damage-state sprites, least damaged
+// first, or nil if damage is drawn as cracks
+func (imgs *Images) GetDamageImages(brickType entities.BrickType) []*ebiten.Image {
+	return imgs.Damage[brickType]
 }
 
-func (imgs *Images) GetBrickImage(brickType entities.BrickType) *ebiten.Image {
-	switch brickType {
-	case entities.BrickTypeColumbia:
-		return imgs.BrickColumbia
-	case entities.BrickTypeSupreme:
-		return imgs.BrickSupreme
-	case entities.BrickTypeTusi:
-		return imgs.BrickTusi
-	case entities.BrickTypeWeed:
-		return imgs.BrickWeed
-	case entities.BrickTypeStandard:
-		fallthrough
-	default:
-		return imgs.BrickStandard
+// loadDamage loads the damage-state sprites of every brick type in config.Brick.
+// Missing sprites are logged and left out of the sequence.
+func (l loader) loadDamage() (map[entities.BrickType][]*ebiten.Image, error) {
+	damage := make(map[entities.BrickType][]*ebiten.Image)
+	var errs []error
+	for key, cfg := range config.Brick {
+		t := entities.BrickType(key)
+		files := cfg.Damage
+		if packFiles, ok := l.pack.damage(key); ok {
+			files = packFiles
+		}
+		for i, sprite := range files {
+			item := fmt.Sprintf("damage.%s.%d", key, i+1)
+			file := path.Join("bricks", sprite)
+			embedded, err := fs.ReadFile(sprites, file)
+			if _, inPack := l.pack.file(item); err != nil && !inPack && !l.exists(file) {
+				log.Printf("Brick type %q: damage sprite %s not found", key, file)
+				continue
+			}
+			img, err := l.load(item, file, embedded)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			damage[t] = append(damage[t], img)
+		}
 	}
+	return damage, errors.Join(errs...)
 }
 
-func (imgs *Images) GetLevelBackground(levelNum int) *ebiten.Image {
-	return imgs.LevelBackground
+// colorBlock is a plain sprite in a brick type's colour
+func colorBlock(t entities.BrickType) *ebiten.Image {
+	img := ebiten.NewImage(60, 20)
+	img.Fill(t.Config().DisplayColor())
+	return img
 }
@@ -0,0 +1,167 @@
+package assets
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"BRIX/config"
+)
+
+// SpriteManifest describes a sprite pack at the root of its directory or zip
+const SpriteManifest = "sprites.json"
+
+// Screens are the full-screen images a sprite pack can replace, by manifest name
+var Screens = []string{"start-1", "start-2", "pause", "level-complete", "game-over", "ball-lost"}
+
+// Manifest maps the sprites a pack replaces to its files. File names are
+// slash-separated and relative to the manifest; anything left out keeps the
+// embedded default.
+type Manifest struct {
+	Name       string              `json:"name,omitempty"`
+	Paddle     string              `json:"paddle,omitempty"`
+	Background string              `json:"background,omitempty"` // default playfield background
+	Bricks     map[string]string   `json:"bricks,omitempty"`     // brick type or alias to file
+	Damage     map[string][]string `json:"damage,omitempty"`     // brick type or alias to damage-state files, least damaged first
+	Screens    map[string]string   `json:"screens,omitempty"`    // name from Screens to file
+}
+
+// SpritePack is a set of replacement sprites read from a directory or zip
+type SpritePack struct {
+	Manifest
+	source string // where the pack was loaded from, for messages
+	fsys   fs.FS
+	files  map[string]string // item name, e.g. "paddle" or "bricks.weed", to file
+}
+
+// OpenSpritePack opens the sprite pack in a directory or zip file. Every problem
+// with the manifest, including files it names that don't exist, is reported.
+func OpenSpritePack(p string) (*SpritePack, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, fmt.Errorf("sprite pack %s: %v", p, err)
+	}
+	if info.IsDir() {
+		return openSpritePack(os.DirFS(p), p)
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("sprite pack %s: %v", p, err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("sprite pack %s: %v", p, err)
+	}
+	return openSpritePack(zr, p)
+}
+
+// openSpritePack reads and checks the manifest of a sprite pack in fsys
+func openSpritePack(fsys fs.FS, source string) (*SpritePack, error) {
+	raw, err := fs.ReadFile(fsys, SpriteManifest)
+	if err != nil {
+		return nil, fmt.Errorf("sprite pack %s: %v", source, err)
+	}
+	var m Manifest
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("sprite pack %s: failed to parse %s: %v", source, SpriteManifest, err)
+	}
+
+	sp := &SpritePack{Manifest: m, source: source, fsys: fsys, files: make(map[string]string)}
+	var problems []string
+	add := func(item, file string) {
+		if _, err := fs.Stat(fsys, file); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: file %q not found", item, file))
+			return
+		}
+		sp.files[item] = file
+	}
+
+	if m.Paddle != "" {
+		add("paddle", m.Paddle)
+	}
+	if m.Background != "" {
+		add("background", m.Background)
+	}
+	for name, file := range m.Bricks {
+		key, ok := config.LookupBrickType(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("bricks: unknown brick type %q", name))
+			continue
+		}
+		add("bricks."+key, file)
+	}
+	for name, files := range m.Damage {
+		key, ok := config.LookupBrickType(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("damage: unknown brick type %q", name))
+			continue
+		}
+		for i, file := range files {
+			add(fmt.Sprintf("damage.%s.%d", key, i+1), file)
+		}
+	}
+	for name, file := range m.Screens {
+		if !slices.Contains(Screens, name) {
+			problems = append(problems, fmt.Sprintf("screens: unknown screen %q (screens are %s)", name, strings.Join(Screens, ", ")))
+			continue
+		}
+		add("screens."+name, file)
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("sprite pack %s:\n  %s", source, strings.Join(problems, "\n  "))
+	}
+	return sp, nil
+}
+
+// Source returns where the pack was loaded from
+func (sp *SpritePack) Source() string {
+	return sp.source
+}
+
+// file returns the pack's file for an item, reporting whether it replaces the item
+func (sp *SpritePack) file(item string) (string, bool) {
+	if sp == nil {
+		return "", false
+	}
+	file, ok := sp.files[item]
+	return file, ok
+}
+
+// damage returns the pack's damage-state files for a brick type, reporting
+// whether it replaces the type's own
+func (sp *SpritePack) damage(key string) ([]string, bool) {
+	if sp == nil {
+		return nil, false
+	}
+	for name, files := range sp.Damage {
+		if k, _ := config.LookupBrickType(name); k == key {
+			return files, true
+		}
+	}
+	return nil, false
+}
+
+// decode decodes the pack's file for an item
+func (sp *SpritePack) decode(item, file string) (*ebiten.Image, error) {
+	data, err := fs.ReadFile(sp.fsys, file)
+	if err == nil {
+		var img *ebiten.Image
+		if img, err = DecodeImage(data); err == nil {
+			return img, nil
+		}
+	}
+	return nil, fmt.Errorf("sprite pack %s: %s: %s: %v", sp.source, item, file, err)
+}
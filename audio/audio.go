@@ -0,0 +1,164 @@
+// Package audio plays the game's sound effects and level music on Ebitengine's
+// audio API. Effects are synthesized, so the game needs no sound files; music
+// is read from the level's pack. Without an audio device everything is silent
+// and the game plays on.
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	ebaudio "github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+
+	"BRIX/entities"
+	"BRIX/settings"
+)
+
+// sampleRate of the audio context and of synthesized effects
+const sampleRate = 48000
+
+// Effect is a kind of sound effect
+type Effect int
+
+const (
+	PaddleHit Effect = iota
+	WallBounce
+	BrickHit     // a brick survived a hit
+	BrickDestroy // a brick took its last hit
+	LifeLost
+	LevelComplete
+)
+
+// Sound is a sound effect to play; brick sounds are pitched by brick type
+type Sound struct {
+	Effect Effect
+	Brick  entities.BrickType // for BrickHit and BrickDestroy
+}
+
+// Audio plays sound effects and music at the volumes in the player's settings
+type Audio struct {
+	ctx      *ebaudio.Context // nil without an audio device
+	settings settings.Settings
+	sounds   map[Sound][]byte // synthesized effects, rendered on first use
+
+	music     *ebaudio.Player
+	musicSrc  MusicSource
+	musicName string
+}
+
+// New opens the audio device. If there is none, the returned Audio is silent.
+func New(s settings.Settings) *Audio {
+	a := &Audio{settings: s, sounds: make(map[Sound][]byte)}
+	if !hasDevice() {
+		log.Printf("No audio device found; playing without sound")
+		return a
+	}
+	a.ctx = ebaudio.NewContext(sampleRate)
+	return a
+}
+
+// Enabled reports whether there is an audio device to play on
+func (a *Audio) Enabled() bool {
+	return a.ctx != nil
+}
+
+// SetSettings changes the volumes, including that of the music already playing
+func (a *Audio) SetSettings(s settings.Settings) {
+	a.settings = s
+	if a.music != nil {
+		a.music.SetVolume(s.EffectiveMusicVolume())
+	}
+}
+
+// Play starts each distinct sound once, so a tick that breaks several bricks of
+// the same type doesn't play the same sound on top of itself
+func (a *Audio) Play(sounds ...Sound) {
+	volume := a.settings.EffectiveSFXVolume()
+	if a.ctx == nil || volume == 0 {
+		return
+	}
+
+	played := make(map[Sound]bool, len(sounds))
+	for _, s := range sounds {
+		if played[s] {
+			continue
+		}
+		played[s] = true
+
+		data, ok := a.sounds[s]
+		if !ok {
+			data = render(s)
+			a.sounds[s] = data
+		}
+		p := a.ctx.NewPlayerF32FromBytes(data)
+		p.SetVolume(volume)
+		p.Play()
+	}
+}
+
+// MusicSource is where music files are read from, such as a level pack or a theme
+type MusicSource interface {
+	ReadFile(name string) ([]byte, error)
+}
+
+// SetMusic loops a music file from src, or stops the music if name is "".
+// Asking for the track already playing leaves it playing. A track that can't be
+// read or decoded is logged and skipped.
+func (a *Audio) SetMusic(src MusicSource, name string) {
+	if src == a.musicSrc && name == a.musicName {
+		return
+	}
+	if a.music != nil {
+		a.music.Close()
+		a.music = nil
+	}
+	a.musicSrc, a.musicName = src, name
+	if a.ctx == nil || src == nil || name == "" {
+		return
+	}
+
+	p, err := a.openMusic(src, name)
+	if err != nil {
+		log.Printf("Failed to play music: %v", err)
+		return
+	}
+	p.SetVolume(a.settings.EffectiveMusicVolume())
+	p.Play()
+	a.music = p
+}
+
+// openMusic decodes a music file from src into a looping player
+func (a *Audio) openMusic(src MusicSource, name string) (*ebaudio.Player, error) {
+	data, err := src.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var stream interface {
+		io.ReadSeeker
+		Length() int64
+	}
+	r := bytes.NewReader(data)
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".ogg":
+		stream, err = vorbis.DecodeF32(r)
+	case ".mp3":
+		stream, err = mp3.DecodeF32(r)
+	case ".wav":
+		stream, err = wav.DecodeF32(r)
+	default:
+		return nil, fmt.Errorf("unsupported music format %q in %s", ext, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", name, err)
+	}
+
+	return a.ctx.NewPlayerF32(ebaudio.NewInfiniteLoopF32(stream, stream.Length()))
+}
@@ -0,0 +1,27 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// hasDevice reports whether ALSA can plausibly open a playback device: a sound
+// card with a playback PCM, or a PulseAudio or PipeWire server that ALSA's
+// default device routes to. Ebitengine stops the game if its audio context
+// fails to open, so it is only created when this is true.
+func hasDevice() bool {
+	if pcms, _ := filepath.Glob("/dev/snd/pcmC*D*p"); len(pcms) > 0 {
+		return true
+	}
+	if os.Getenv("PULSE_SERVER") != "" {
+		return true
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		for _, name := range []string{"pulse/native", "pipewire-0"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package audio
+
+// hasDevice reports whether an audio device is available. Outside Linux the
+// platform audio APIs always provide one.
+func hasDevice() bool {
+	return true
+}
@@ -0,0 +1,135 @@
+package audio
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"time"
+
+	"BRIX/entities"
+)
+
+// waveform is the shape of a synthesized tone
+type waveform int
+
+const (
+	sine waveform = iota
+	square
+	triangle
+	noise
+)
+
+// tone renders a note as stereo 32-bit float PCM. The pitch slides from f0 to
+// f1 over the note and the volume decays to silence, which keeps short effects
+// from clicking.
+func tone(wave waveform, f0, f1 float64, dur time.Duration, volume float64) []byte {
+	n := int(dur.Seconds() * sampleRate)
+	out := make([]byte, n*8)
+	phase := 0.0
+	seed := uint32(1)
+	for i := 0; i < n; i++ {
+		t := float64(i) / float64(n)
+		phase += (f0 + (f1-f0)*t) / sampleRate
+		phase -= math.Floor(phase)
+
+		var v float64
+		switch wave {
+		case sine:
+			v = math.Sin(2 * math.Pi * phase)
+		case square:
+			v = 1
+			if phase >= 0.5 {
+				v = -1
+			}
+		case triangle:
+			v = 4*math.Abs(phase-0.5) - 1
+		case noise:
+			seed = seed*1664525 + 1013904223
+			v = float64(seed)/math.MaxUint32*2 - 1
+		}
+
+		// A few milliseconds of attack, then a quadratic decay
+		env := (1 - t) * (1 - t)
+		if attack := float64(i) / (0.004 * sampleRate); attack < 1 {
+			env *= attack
+		}
+
+		sample := math.Float32bits(float32(v * env * volume))
+		binary.LittleEndian.PutUint32(out[i*8:], sample)
+		binary.LittleEndian.PutUint32(out[i*8+4:], sample)
+	}
+	return out
+}
+
+// mix adds rendered sounds together, sample by sample
+func mix(sounds ...[]byte) []byte {
+	var out []byte
+	for _, s := range sounds {
+		if len(s) > len(out) {
+			out = append(out, make([]byte, len(s)-len(out))...)
+		}
+		for i := 0; i+4 <= len(s); i += 4 {
+			a := math.Float32frombits(binary.LittleEndian.Uint32(out[i:]))
+			b := math.Float32frombits(binary.LittleEndian.Uint32(s[i:]))
+			binary.LittleEndian.PutUint32(out[i:], math.Float32bits(a+b))
+		}
+	}
+	return out
+}
+
+// concat plays rendered sounds one after another
+func concat(sounds ...[]byte) []byte {
+	var out []byte
+	for _, s := range sounds {
+		out = append(out, s...)
+	}
+	return out
+}
+
+// brickPitches gives each builtin brick type its own note
+var brickPitches = map[entities.BrickType]float64{
+	"standard": 523.25, // C5
+	"tusi":     587.33, // D5
+	"weed":     659.25, // E5
+	"columbia": 783.99, // G5
+	"supreme":  880.00, // A5
+}
+
+// pentatonic is the scale other brick types are spread over, so any mix of types sounds in key
+var pentatonic = []float64{523.25, 587.33, 659.25, 783.99, 880.00, 1046.50, 1174.66, 1318.51}
+
+// brickPitch returns the note a brick type sounds at
+func brickPitch(t entities.BrickType) float64 {
+	if f, ok := brickPitches[t]; ok {
+		return f
+	}
+	h := fnv.New32a()
+	h.Write([]byte(t))
+	return pentatonic[h.Sum32()%uint32(len(pentatonic))]
+}
+
+// render synthesizes a sound effect
+func render(s Sound) []byte {
+	const ms = time.Millisecond
+	switch s.Effect {
+	case PaddleHit:
+		return tone(square, 330, 370, 70*ms, 0.25)
+	case WallBounce:
+		return tone(triangle, 190, 170, 35*ms, 0.35)
+	case BrickHit:
+		return tone(triangle, brickPitch(s.Brick), brickPitch(s.Brick), 60*ms, 0.4)
+	case BrickDestroy:
+		f := brickPitch(s.Brick)
+		return mix(tone(square, f, f*2, 120*ms, 0.2), tone(noise, 0, 0, 90*ms, 0.12))
+	case LifeLost:
+		return tone(square, 520, 130, 600*ms, 0.25)
+	case LevelComplete:
+		return concat(
+			tone(sine, 523.25, 523.25, 110*ms, 0.4),
+			tone(sine, 659.25, 659.25, 110*ms, 0.4),
+			tone(sine, 783.99, 783.99, 110*ms, 0.4),
+			tone(sine, 1046.50, 1046.50, 320*ms, 0.4),
+		)
+	}
+	return nil
+}
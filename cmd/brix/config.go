@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"BRIX/config"
+)
+
+// runConfig prints every config value and the layer it came from, with any
+// directories given applied as overrides like the game's -config flag
+func runConfig(args []string) int {
+	if err := config.Load(args...); err != nil {
+		fmt.Fprintf(os.Stderr, "brix: failed to load config: %v\n", err)
+		return 1
+	}
+	for _, l := range config.Layers(args...) {
+		fmt.Printf("# layer: %s\n", l.Name)
+	}
+	for _, v := range config.Sources() {
+		fmt.Printf("%s = %s  (%s)\n", v.Key, v.Value, v.Source)
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"BRIX/config"
+	"BRIX/entities"
+	"BRIX/levels"
+	"BRIX/sim"
+)
+
+// typeStats totals the bricks of one type in a level
+type typeStats struct {
+	bricks, hits, score int
+}
+
+// runInfo prints brick counts per type, total hits and the most points a
+// level's bricks can award.
+func runInfo(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "brix: failed to load config: %v\n", err)
+		return 1
+	}
+
+	code := 0
+	seen := make(map[string]bool)
+	for _, path := range args {
+		files, err := readLevels(path, seen)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			code = 1
+			continue
+		}
+		for _, f := range files {
+			if f.err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", f.err)
+				code = 1
+				continue
+			}
+			printInfo(f.name, f.level)
+		}
+	}
+	return code
+}
+
+func printInfo(name string, level *levels.Level) {
+	format := "grid"
+	if level.UsePixelPositioning {
+		format = "pixel"
+	}
+	fmt.Printf("%s: %q (%s format)\n", name, level.Name, format)
+
+	// Scores are read at full lives, the most a brick can be worth
+	lives := strconv.Itoa(sim.StartingLives)
+	stats := make(map[entities.BrickType]*typeStats)
+	var total typeStats
+	for _, lb := range level.Bricks {
+		t := entities.ParseBrickType(lb.TypeName())
+		s := stats[t]
+		if s == nil {
+			s = &typeStats{}
+			stats[t] = s
+		}
+
+		// Unbreakable bricks can be hit forever, so only breakable ones count
+		score := 0
+		if cfg := t.Config(); !cfg.Has(config.BehaviourUnbreakable) {
+			score = cfg.Points.Destroy[lives]
+			if lb.Hits > 1 {
+				score += (lb.Hits - 1) * cfg.Points.Hit[lives]
+			}
+		}
+		s.bricks++
+		s.hits += lb.Hits
+		s.score += score
+		total.bricks++
+		total.hits += lb.Hits
+		total.score += score
+	}
+
+	types := make([]string, 0, len(stats))
+	for t := range stats {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		s := stats[entities.BrickType(t)]
+		fmt.Printf("  %-10s %4d bricks %5d hits %7d points\n", t, s.bricks, s.hits, s.score)
+	}
+	fmt.Printf("  %-10s %4d bricks %5d hits %7d points max (bricks only, at %s lives)\n",
+		"total", total.bricks, total.hits, total.score, lives)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"BRIX/config"
+	"BRIX/levels"
+	"BRIX/themes"
+)
+
+// runLint validates every level named on the command line and prints each
+// problem as file:line: message. It returns 1 if any problem was found.
+func runLint(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	// Brick types come from the config, including any the user added
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "brix: failed to load config: %v\n", err)
+		return 1
+	}
+
+	checked, found := 0, 0
+	// Levels may use the user's own themes; a theme that fails to load is a problem too
+	if err := themes.Load(); err != nil {
+		fmt.Println(err)
+		found++
+	}
+	seen := make(map[string]bool)
+	for _, path := range args {
+		files, err := readLevels(path, seen)
+		if err != nil {
+			fmt.Printf("%s: %v\n", path, err)
+			found++
+			continue
+		}
+		for _, f := range files {
+			checked++
+			err := f.err
+			if err == nil {
+				err = levels.PrepareLevel(f.level, f.name)
+			}
+			for _, p := range levels.AsProblems(err) {
+				if p.File == "" {
+					p.File = f.name
+				}
+				fmt.Println(p)
+				found++
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%d levels checked, %d problems\n", checked, found)
+	if found > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Behaviours a brick type may list in brick_types.json
+const (
+	// BehaviourUnbreakable bricks deflect the ball but are never destroyed, and
+	// don't need clearing to complete a level
+	BehaviourUnbreakable = "unbreakable"
+)
+
+// Behaviours lists every behaviour brick types may use
+var Behaviours = []string{BehaviourUnbreakable}
+
+// brickNames maps every accepted brick type name, keys and aliases alike, to its key
+var brickNames map[string]string
+
+// indexBrickTypes checks the brick palette and maps every name and alias to its
+// type's key. Every problem is reported, not just the first.
+func indexBrickTypes(bricks BrickTypes) (map[string]string, error) {
+	keys := make([]string, 0, len(bricks))
+	for key := range bricks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	names := make(map[string]string, len(bricks))
+	for _, key := range keys {
+		names[key] = key
+	}
+
+	var errs []error
+	for _, key := range keys {
+		cfg := bricks[key]
+		if cfg.Hits <= 0 {
+			errs = append(errs, fmt.Errorf("brick type %q: hits must be positive, not %d", key, cfg.Hits))
+		}
+		if _, err := ParseColor(cfg.Color); err != nil {
+			errs = append(errs, fmt.Errorf("brick type %q: %v", key, err))
+		}
+		for _, b := range cfg.Behaviours {
+			if !slices.Contains(Behaviours, b) {
+				errs = append(errs, fmt.Errorf("brick type %q: unknown behaviour %q (want one of %s)",
+					key, b, strings.Join(Behaviours, ", ")))
+			}
+		}
+		for _, alias := range cfg.Aliases {
+			if other, ok := names[alias]; ok {
+				errs = append(errs, fmt.Errorf("brick type %q: alias %q is already a name of %q", key, alias, other))
+				continue
+			}
+			names[alias] = key
+		}
+	}
+	return names, errors.Join(errs...)
+}
+
+// LookupBrickType resolves a brick type name or alias from level data to the
+// type's key, reporting whether the name is known
+func LookupBrickType(name string) (string, bool) {
+	key, ok := brickNames[name]
+	return key, ok
+}
+
+// BrickTypeNames returns every accepted brick type name, aliases included, in sorted order
+func BrickTypeNames() []string {
+	names := make([]string, 0, len(brickNames))
+	for name := range brickNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DisplayColor returns the type's colour, or grey if it isn't valid
+func (c BrickTypeCfg) DisplayColor() color.RGBA {
+	rgba, err := ParseColor(c.Color)
+	if err != nil {
+		return color.RGBA{200, 200, 200, 255}
+	}
+	return rgba
+}
+
+// Has reports whether the type lists a behaviour
+func (c BrickTypeCfg) Has(behaviour string) bool {
+	return slices.Contains(c.Behaviours, behaviour)
+}
+
+// ParseColor parses a colour written as "#rrggbb" or "#rrggbbaa"
+func ParseColor(s string) (color.RGBA, error) {
+	var c color.RGBA
+	c.A = 255
+	var err error
+	switch len(s) {
+	case 7:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 9:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		err = errors.New("wrong length")
+	}
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("color %q must be #rrggbb or #rrggbbaa", s)
+	}
+	return c, nil
+}
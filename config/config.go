@@ -1,22 +1,35 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"os"
 )
 
 // BrickTypeCfg describes a single brick type loaded from brick_types.json.
+// Everything about a type comes from here, so adding one needs no code changes.
 type BrickTypeCfg struct {
-	Name        string  `json:"name"`
-	Sprite      string  `json:"sprite"`
-	Hits        int     `json:"hits"`
-	SpeedFactor float64 `json:"speedFactor"`
-	PowerUp     string  `json:"powerUp"`
+	Name        string      `json:"name"`
+	Sprite      string      `json:"sprite"`            // file under assets/bricks; drawn in Color if missing
+	Damage      []string    `json:"damage,omitempty"`  // files under assets/bricks for increasingly damaged bricks; cracks are drawn without them
+	Hits        int         `json:"hits"`              // hits given to new bricks of this type in the editor
+	Color       string      `json:"color"`             // "#rrggbb", for bricks without a sprite and for effects
+	Aliases     []string    `json:"aliases,omitempty"` // other names levels may use, e.g. legacy colour names
+	Points      BrickPoints `json:"points"`
+	SpeedFactor float64     `json:"speedFactor"`
+	PowerUp     string      `json:"powerUp"`
+	Behaviours  []string    `json:"behaviours,omitempty"` // see Behaviours
 }
 
-// BrickTypes maps a brick shorthand / name to its config.
-// Example keys: "default", "green", "blue".
+// BrickPoints are the points a brick type awards, by remaining lives
+type BrickPoints struct {
+	Hit     PointsByLives `json:"hit"`     // for a hit the brick survives
+	Destroy PointsByLives `json:"destroy"` // for the hit that destroys it
+}
+
+// BrickTypes maps a brick type's key, as used in level files, to its config.
+// Example keys: "standard", "weed".
 type BrickTypes map[string]BrickTypeCfg
 
 // PointsByLives stores points keyed by remaining lives ("3","2","1").
@@ -24,11 +37,9 @@ type PointsByLives map[string]int
 
 // ScoringConfig controls point economy and now supports per-lives values.
 type ScoringConfig struct {
-	PaddleHit    PointsByLives            `json:"paddleHit"`
-	LifeBonus    int                      `json:"lifeBonus"`
-	BrickHit     map[string]PointsByLives `json:"brickHit"`
-	BrickDestroy map[string]PointsByLives `json:"brickDestroy"`
-	PowerUp      map[string]PointsByLives `json:"powerUp"`
+	PaddleHit PointsByLives            `json:"paddleHit"`
+	LifeBonus int                      `json:"lifeBonus"`
+	PowerUp   map[string]PointsByLives `json:"powerUp"`
 }
 
 var (
@@ -38,42 +49,64 @@ var (
 	Score ScoringConfig
 )
 
-// Load reads brick_types.json and scoring.json into memory. Call this once at program start.
-func Load() error {
-	if err := loadBrickTypes("config/brick_types.json"); err != nil {
-		return fmt.Errorf("load brick types: %w", err)
-	}
-	if err := loadScoring("config/scoring.json"); err != nil {
-		return fmt.Errorf("load scoring: %w", err)
+// Load builds the brick palette and scoring rules from every config layer (see
+// Layers), merging the JSON key by key so an override file only needs the
+// values it changes. extraDirs are applied last, e.g. from the --config flag.
+// Call this once at program start.
+func Load(extraDirs ...string) error {
+	return load(Layers(extraDirs...))
+}
+
+// init loads the embedded defaults so the brick palette is usable before Load,
+// e.g. by tools that only validate levels
+func init() {
+	if err := load([]Layer{{Name: DefaultsSource, FS: defaults}}); err != nil {
+		panic(fmt.Sprintf("config: embedded defaults are invalid: %v", err))
 	}
-	return nil
 }
 
-func loadBrickTypes(path string) error {
-	raw, err := os.ReadFile(path)
-	if err != nil {
-		return err
+// load builds the config from layers and replaces the current one on success
+func load(layers []Layer) error {
+	values := make(map[string]ValueSource)
+
+	var bricks BrickTypes
+	if err := loadFile(layers, BrickTypesFile, &bricks, values); err != nil {
+		return fmt.Errorf("load brick types: %w", err)
 	}
-	var m BrickTypes
-	if err := json.Unmarshal(raw, &m); err != nil {
-		return err
+	if len(bricks) == 0 {
+		return fmt.Errorf("%s contains no entries", BrickTypesFile)
 	}
-	if len(m) == 0 {
-		return fmt.Errorf("brick_types.json contains no entries")
+	names, err := indexBrickTypes(bricks)
+	if err != nil {
+		return fmt.Errorf("load brick types: %w", err)
+	}
+
+	var scoring ScoringConfig
+	if err := loadFile(layers, ScoringFile, &scoring, values); err != nil {
+		return fmt.Errorf("load scoring: %w", err)
 	}
-	Brick = m
+
+	Brick, Score, sources, brickNames = bricks, scoring, values, names
 	return nil
 }
 
-func loadScoring(path string) error {
-	raw, err := os.ReadFile(path)
+// BrickTypesHash returns a stable digest of the loaded brick palette.
+func BrickTypesHash() string {
+	return hashJSON(Brick)
+}
+
+// ScoringHash returns a stable digest of the loaded scoring rules.
+func ScoringHash() string {
+	return hashJSON(Score)
+}
+
+// hashJSON digests the JSON encoding of v; map keys are sorted by encoding/json
+// so equal configs always hash the same.
+func hashJSON(v any) string {
+	raw, err := json.Marshal(v)
 	if err != nil {
-		return err
+		return ""
 	}
-	var s ScoringConfig
-	if err := json.Unmarshal(raw, &s); err != nil {
-		return err
-	}
-	Score = s
-	return nil
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Files every config layer may provide
+const (
+	BrickTypesFile = "brick_types.json"
+	ScoringFile    = "scoring.json"
+)
+
+// DefaultsSource names the embedded defaults when reporting where a value came from
+const DefaultsSource = "embedded defaults"
+
+//go:embed brick_types.json scoring.json
+var defaults embed.FS
+
+// Layer is one source of config files. Later layers override earlier ones.
+type Layer struct {
+	Name string // shown when reporting where a value came from
+	FS   fs.FS
+}
+
+// ValueSource records the layer a single config value was taken from
+type ValueSource struct {
+	Key    string // file name without .json followed by the JSON path, e.g. brick_types.weed.points.hit.3
+	Value  string // the value as JSON
+	Source string // Layer.Name
+}
+
+// movedKeys are keys older config files used that now live elsewhere, so a
+// stale override gets a pointer to the new place instead of a bare decode error
+var movedKeys = []struct {
+	file, key, to string
+}{
+	{ScoringFile, "brickHit", `"points.hit" of each brick type in ` + BrickTypesFile},
+	{ScoringFile, "brickDestroy", `"points.destroy" of each brick type in ` + BrickTypesFile},
+}
+
+// sources holds where each value of the last Load came from, keyed by ValueSource.Key
+var sources map[string]ValueSource
+
+// Layers returns the config sources in the order they are applied: the defaults
+// embedded in the binary, the config/ directory when run from the source tree,
+// BRIX/config in the user's config directory, then extraDirs.
+func Layers(extraDirs ...string) []Layer {
+	layers := []Layer{{Name: DefaultsSource, FS: defaults}}
+
+	dirs := []string{"config"}
+	if cfg, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(cfg, "BRIX", "config"))
+	}
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			layers = append(layers, Layer{Name: dir, FS: os.DirFS(dir)})
+		}
+	}
+
+	// Directories asked for explicitly are always used so a typo in the path is reported
+	for _, dir := range extraDirs {
+		layers = append(layers, Layer{Name: dir, FS: os.DirFS(dir)})
+	}
+	return layers
+}
+
+// Sources reports where every value of the loaded config came from, sorted by key
+func Sources() []ValueSource {
+	list := make([]ValueSource, 0, len(sources))
+	for _, v := range sources {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Key < list[j].Key })
+	return list
+}
+
+// Source returns the layer a config value came from, e.g. Source("scoring.lifeBonus")
+func Source(key string) string {
+	return sources[key].Source
+}
+
+// loadFile merges file from every layer that has it and decodes the result into v
+func loadFile(layers []Layer, file string, v any, values map[string]ValueSource) error {
+	merged := make(map[string]any)
+	prefix := strings.TrimSuffix(file, filepath.Ext(file))
+	found := false
+
+	for _, l := range layers {
+		raw, err := fs.ReadFile(l.FS, file)
+		if errors.Is(err, fs.ErrNotExist) && l.Name != DefaultsSource {
+			if _, dirErr := fs.Stat(l.FS, "."); dirErr != nil {
+				return fmt.Errorf("config directory %s: %v", l.Name, dirErr)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", filepath.Join(l.Name, file), err)
+		}
+
+		var layer map[string]any
+		if err := json.Unmarshal(raw, &layer); err != nil {
+			return fmt.Errorf("%s: %v", filepath.Join(l.Name, file), err)
+		}
+		for _, m := range movedKeys {
+			if _, ok := layer[m.key]; ok && m.file == file {
+				return fmt.Errorf("%s: %q has moved to %s", filepath.Join(l.Name, file), m.key, m.to)
+			}
+		}
+		mergeValues(merged, layer, prefix, l.Name, values)
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("%s not found in any config layer", file)
+	}
+
+	// Decode strictly so a misspelt key in an override isn't silently ignored
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	return nil
+}
+
+// mergeValues copies src into dst, descending into objects present in both so
+// that only the leaves src sets are replaced. Each leaf src changes is recorded
+// in values as coming from layer.
+func mergeValues(dst, src map[string]any, path, layer string, values map[string]ValueSource) {
+	for k, v := range src {
+		key := path + "." + k
+
+		if obj, ok := v.(map[string]any); ok {
+			existing, ok := dst[k].(map[string]any)
+			if !ok {
+				forget(values, key)
+				existing = make(map[string]any)
+				dst[k] = existing
+			}
+			mergeValues(existing, obj, key, layer, values)
+			continue
+		}
+
+		raw, _ := json.Marshal(v)
+		if prev, ok := values[key]; ok && prev.Value == string(raw) {
+			continue // same value as an earlier layer; keep crediting that one
+		}
+		forget(values, key)
+		dst[k] = v
+		values[key] = ValueSource{Key: key, Value: string(raw), Source: layer}
+	}
+}
+
+// forget drops the recorded sources of key and everything beneath it, for when an
+// override replaces a value with one of a different shape
+func forget(values map[string]ValueSource, key string) {
+	delete(values, key)
+	for k := range values {
+		if strings.HasPrefix(k, key+".") {
+			delete(values, k)
+		}
+	}
+}
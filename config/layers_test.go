@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeLayer writes config files into dir, creating it
+func writeLayer(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// useTestLayers runs the test in an empty directory with its own user config
+// directory, and restores the embedded defaults afterwards
+func useTestLayers(t *testing.T) (root, userDir string) {
+	root = t.TempDir()
+	t.Chdir(root)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Setenv("HOME", root)
+	userDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := load([]Layer{{Name: DefaultsSource, FS: defaults}}); err != nil {
+			t.Fatalf("restoring defaults: %v", err)
+		}
+	})
+	return root, filepath.Join(userDir, "BRIX", "config")
+}
+
+func TestLoadLayers(t *testing.T) {
+	root, userDir := useTestLayers(t)
+	writeLayer(t, "config", map[string]string{
+		ScoringFile: `{"paddleHit": {"3": 5}, "lifeBonus": 100}`,
+	})
+	writeLayer(t, userDir, map[string]string{
+		BrickTypesFile: `{"weed": {"points": {"hit": {"3": 99}}}}`,
+	})
+	override := filepath.Join(root, "override")
+	writeLayer(t, override, map[string]string{
+		ScoringFile: `{"lifeBonus": 500, "paddleHit": {"1": 0}}`,
+	})
+
+	if err := Load(override); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		got    int
+		want   int
+		source string
+	}{
+		{"scoring.paddleHit.3", Score.PaddleHit["3"], 5, "config"},
+		{"scoring.paddleHit.2", Score.PaddleHit["2"], 0, DefaultsSource},
+		// The override repeats the default, so the default keeps the credit
+		{"scoring.paddleHit.1", Score.PaddleHit["1"], 0, DefaultsSource},
+		{"scoring.lifeBonus", Score.LifeBonus, 500, override},
+		{"brick_types.weed.points.hit.3", Brick["weed"].Points.Hit["3"], 99, userDir},
+		{"brick_types.weed.points.hit.2", Brick["weed"].Points.Hit["2"], 8, DefaultsSource},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.key, tt.got, tt.want)
+		}
+		if got := Source(tt.key); got != tt.source {
+			t.Errorf("Source(%q) = %q, want %q", tt.key, got, tt.source)
+		}
+	}
+
+	// Sources lists the same values, sorted by key
+	list := Sources()
+	for i := 1; i < len(list); i++ {
+		if list[i-1].Key >= list[i].Key {
+			t.Fatalf("Sources not sorted: %q before %q", list[i-1].Key, list[i].Key)
+		}
+	}
+	for _, v := range list {
+		if v.Key == "scoring.lifeBonus" && (v.Value != "500" || v.Source != override) {
+			t.Errorf("Sources has %+v for scoring.lifeBonus", v)
+		}
+	}
+}
+
+func TestLoadRejectsMovedKeys(t *testing.T) {
+	root, _ := useTestLayers(t)
+	override := filepath.Join(root, "override")
+	writeLayer(t, override, map[string]string{
+		ScoringFile: `{"brickHit": {"weed": {"3": 1}}}`,
+	})
+
+	before := ScoringHash()
+	err := Load(override)
+	if err == nil || !strings.Contains(err.Error(), `"brickHit" has moved to "points.hit"`) {
+		t.Errorf("Load error = %v, want one pointing brickHit at points.hit", err)
+	}
+	if ScoringHash() != before {
+		t.Errorf("failed Load changed the config")
+	}
+}
@@ -0,0 +1,539 @@
+// Package editor implements the in-game level editor's model: placing, moving,
+// resizing and deleting bricks, the brick palette, switching a level between
+// grid and pixel positioning, undo/redo and saving. It has no dependency on
+// Ebitengine; the game package feeds it mouse and keyboard events.
+package editor
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"BRIX/config"
+	"BRIX/entities"
+	"BRIX/levels"
+)
+
+const (
+	maxUndo      = 100
+	handleSize   = 14.0 // px square at a brick's bottom-right corner that resizes it
+	pixelSnap    = 5    // pixel-mode positions and sizes snap to this many px
+	minBrickSize = 10   // px
+)
+
+// Palette layout inside the HUD bar
+const (
+	paletteX   = 860.0
+	paletteY   = 12.0
+	paletteW   = 80.0
+	paletteH   = 36.0
+	paletteGap = 8.0
+)
+
+// Editor is the level currently being edited together with the editing state
+type Editor struct {
+	pack     *levels.Pack
+	levelNum int
+	level    *levels.Level     // level as it will be written to disk
+	bricks   []*entities.Brick // on-screen bricks, rebuilt after every change
+
+	palette []entities.BrickType
+	current int // palette index for new bricks
+	hits    int // hits for new bricks
+
+	selected int // index into level.Bricks, -1 for none
+
+	undo, redo []*levels.Level
+	dragStart  *levels.Level // snapshot taken when a drag began
+
+	dirty        bool
+	discardArmed bool // unsaved changes were reported; the next discard goes ahead
+	status       string
+}
+
+// New creates an editor with the palette from config.Brick and opens levelNum of pack
+func New(pack *levels.Pack, levelNum int) *Editor {
+	e := &Editor{pack: pack, selected: -1}
+
+	names := make([]string, 0, len(config.Brick))
+	for name := range config.Brick {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		e.palette = append(e.palette, entities.BrickType(name))
+	}
+	e.SetType(0)
+
+	e.Open(levelNum)
+	return e
+}
+
+// Open loads levelNum for editing, starting an empty level past the end of the pack
+func (e *Editor) Open(levelNum int) {
+	e.levelNum = levelNum
+	e.selected = -1
+	e.undo, e.redo, e.dragStart = nil, nil, nil
+	e.dirty, e.discardArmed = false, false
+
+	if levelNum > e.pack.Len() {
+		e.level = newLevel(levelNum)
+		e.status = fmt.Sprintf("New level %d", levelNum)
+	} else if level, err := e.pack.ReadLevel(levelNum); err != nil {
+		e.level = newLevel(levelNum)
+		e.status = err.Error()
+	} else {
+		e.level = level
+		e.status = fmt.Sprintf("Opened %s", e.pack.LevelName(levelNum))
+	}
+	if !e.pack.Writable() {
+		e.status += " (read-only pack)"
+	}
+
+	e.rebuild()
+}
+
+// newLevel creates an empty pixel-format level
+func newLevel(levelNum int) *levels.Level {
+	return &levels.Level{
+		Name:                fmt.Sprintf("Level %d", levelNum),
+		UsePixelPositioning: true,
+		DefaultBrickWidth:   150,
+		DefaultBrickHeight:  60,
+		BallSpeed:           400,
+	}
+}
+
+// view returns the level as the game will play it (grid levels auto-fitted)
+func (e *Editor) view() *levels.Level {
+	v := e.level.Clone()
+	if !v.UsePixelPositioning {
+		levels.AutoFitLevel(v)
+	}
+	return v
+}
+
+// rebuild recreates the on-screen bricks after the level changed
+func (e *Editor) rebuild() {
+	e.bricks = levels.BuildBricks(e.view())
+	if e.selected >= len(e.level.Bricks) {
+		e.selected = -1
+	}
+}
+
+// checkpoint records the current level for undo before a change
+func (e *Editor) checkpoint() {
+	e.pushUndo(e.level.Clone())
+}
+
+func (e *Editor) pushUndo(snapshot *levels.Level) {
+	e.undo = append(e.undo, snapshot)
+	if len(e.undo) > maxUndo {
+		e.undo = e.undo[1:]
+	}
+	e.redo = nil
+	e.dirty = true
+	e.discardArmed = false
+}
+
+// LevelNum returns the number of the level being edited
+func (e *Editor) LevelNum() int {
+	return e.levelNum
+}
+
+// Pack returns the level pack being edited
+func (e *Editor) Pack() *levels.Pack {
+	return e.pack
+}
+
+// MaxLevel returns the highest level number that can be opened: one past the
+// end of the pack starts a new level
+func (e *Editor) MaxLevel() int {
+	return e.pack.Len() + 1
+}
+
+// Level returns the level being edited
+func (e *Editor) Level() *levels.Level {
+	return e.level
+}
+
+// PlaytestLevel returns a copy of the level prepared exactly as the game would load it
+func (e *Editor) PlaytestLevel() *levels.Level {
+	return e.view()
+}
+
+// Bricks returns the bricks as they appear on screen, in level order
+func (e *Editor) Bricks() []*entities.Brick {
+	return e.bricks
+}
+
+// Selected returns the index of the selected brick, or -1
+func (e *Editor) Selected() int {
+	return e.selected
+}
+
+// Palette returns the brick types available for placement
+func (e *Editor) Palette() []entities.BrickType {
+	return e.palette
+}
+
+// Current returns the palette index used for new bricks
+func (e *Editor) Current() int {
+	return e.current
+}
+
+// Hits returns the hit count used for new bricks
+func (e *Editor) Hits() int {
+	return e.hits
+}
+
+// GridMode reports whether the level uses grid positioning
+func (e *Editor) GridMode() bool {
+	return !e.level.UsePixelPositioning
+}
+
+// Dirty reports whether there are unsaved changes
+func (e *Editor) Dirty() bool {
+	return e.dirty
+}
+
+// Status returns the last status message
+func (e *Editor) Status() string {
+	return e.status
+}
+
+// ConfirmDiscard reports whether unsaved changes may be thrown away. The first
+// call with unsaved changes only warns; calling again confirms.
+func (e *Editor) ConfirmDiscard() bool {
+	if !e.dirty || e.discardArmed {
+		return true
+	}
+	e.discardArmed = true
+	e.status = "Unsaved changes! Ctrl+S to save, or repeat to discard"
+	return false
+}
+
+// BrickAt returns the index of the topmost brick containing the point, or -1
+func (e *Editor) BrickAt(x, y float64) int {
+	for i := len(e.bricks) - 1; i >= 0; i-- {
+		left, top, right, bottom := e.bricks[i].GetBounds()
+		if x >= left && x <= right && y >= top && y <= bottom {
+			return i
+		}
+	}
+	return -1
+}
+
+// OnResizeHandle reports whether the point is on brick i's resize handle
+func (e *Editor) OnResizeHandle(i int, x, y float64) bool {
+	if i < 0 || i >= len(e.bricks) {
+		return false
+	}
+	_, _, right, bottom := e.bricks[i].GetBounds()
+	return x >= right-handleSize && x <= right && y >= bottom-handleSize && y <= bottom
+}
+
+// Select selects brick i (or nothing for -1) and loads its type and hits into the palette
+func (e *Editor) Select(i int) {
+	if i < 0 || i >= len(e.level.Bricks) {
+		e.selected = -1
+		return
+	}
+	e.selected = i
+	lb := e.level.Bricks[i]
+	e.hits = lb.Hits
+	t := entities.ParseBrickType(lb.TypeName())
+	for idx, p := range e.palette {
+		if p == t {
+			e.current = idx
+		}
+	}
+}
+
+// SetType picks palette entry idx for new bricks and retypes the selected brick
+func (e *Editor) SetType(idx int) {
+	if idx < 0 || idx >= len(e.palette) {
+		return
+	}
+	e.current = idx
+	e.hits = config.Brick[string(e.palette[idx])].Hits
+	if e.hits <= 0 {
+		e.hits = 1
+	}
+
+	if e.selected >= 0 {
+		e.checkpoint()
+		lb := &e.level.Bricks[e.selected]
+		setBrickType(lb, string(e.palette[idx]), e.level.UsePixelPositioning)
+		lb.Hits = e.hits
+		e.rebuild()
+	}
+}
+
+// CycleType moves through the palette by delta entries
+func (e *Editor) CycleType(delta int) {
+	if len(e.palette) == 0 {
+		return
+	}
+	e.SetType(((e.current+delta)%len(e.palette) + len(e.palette)) % len(e.palette))
+}
+
+// AdjustHits changes the hits for new bricks and the selected brick
+func (e *Editor) AdjustHits(delta int) {
+	e.hits += delta
+	if e.hits < 1 {
+		e.hits = 1
+	}
+	if e.selected >= 0 {
+		e.checkpoint()
+		e.level.Bricks[e.selected].Hits = e.hits
+		e.rebuild()
+	}
+}
+
+// Place adds a brick of the current type centred as close to (x, y) as the format allows
+func (e *Editor) Place(x, y float64) {
+	lb := entities.LevelBrick{Hits: e.hits}
+	setBrickType(&lb, string(e.palette[e.current]), e.level.UsePixelPositioning)
+
+	if e.level.UsePixelPositioning {
+		w, h := float64(e.level.DefaultBrickWidth), float64(e.level.DefaultBrickHeight)
+		lb.PixelX, lb.PixelY = clampPixel(x-w/2, y-h/2, w, h)
+	} else {
+		cx, cy, ok := e.nearestCell(x, y, -1)
+		if !ok {
+			e.status = "No free cell in this row"
+			return
+		}
+		lb.X, lb.Y = cx, cy
+	}
+
+	e.checkpoint()
+	e.level.Bricks = append(e.level.Bricks, lb)
+	e.selected = len(e.level.Bricks) - 1
+	e.rebuild()
+}
+
+// Delete removes brick i
+func (e *Editor) Delete(i int) {
+	if i < 0 || i >= len(e.level.Bricks) {
+		return
+	}
+	e.checkpoint()
+	e.level.Bricks = append(e.level.Bricks[:i], e.level.Bricks[i+1:]...)
+	e.selected = -1
+	e.rebuild()
+}
+
+// BeginDrag starts a move or resize; the whole drag becomes a single undo step
+func (e *Editor) BeginDrag() {
+	e.dragStart = e.level.Clone()
+}
+
+// EndDrag finishes a drag, recording it for undo if anything changed
+func (e *Editor) EndDrag() {
+	if e.dragStart == nil {
+		return
+	}
+	// Compare clones: they leave out the file positions a freshly opened level still has
+	if !reflect.DeepEqual(e.dragStart, e.level.Clone()) {
+		e.pushUndo(e.dragStart)
+	}
+	e.dragStart = nil
+}
+
+// DragMove moves brick i so it is centred as close to (x, y) as the format allows
+func (e *Editor) DragMove(i int, x, y float64) {
+	if i < 0 || i >= len(e.level.Bricks) {
+		return
+	}
+	lb := &e.level.Bricks[i]
+
+	if e.level.UsePixelPositioning {
+		left, top, right, bottom := e.bricks[i].GetBounds()
+		w, h := right-left, bottom-top
+		lb.PixelX, lb.PixelY = clampPixel(x-w/2, y-h/2, w, h)
+	} else {
+		cx, cy, ok := e.nearestCell(x, y, i)
+		if !ok {
+			return
+		}
+		lb.X, lb.Y = cx, cy
+	}
+	e.rebuild()
+}
+
+// DragResize moves brick i's bottom-right corner to (x, y). Grid levels share
+// one brick size, so resizing any brick resizes them all.
+func (e *Editor) DragResize(i int, x, y float64) {
+	if i < 0 || i >= len(e.level.Bricks) {
+		return
+	}
+	left, top, _, _ := e.bricks[i].GetBounds()
+	w := max(minBrickSize, snap(x-left))
+	h := max(minBrickSize, snap(y-top))
+
+	if e.level.UsePixelPositioning {
+		lb := &e.level.Bricks[i]
+		lb.Width, lb.Height = w, h
+	} else {
+		e.level.BrickWidth, e.level.BrickHeight = w, h
+	}
+	e.rebuild()
+}
+
+// Undo reverts the last change
+func (e *Editor) Undo() {
+	if len(e.undo) == 0 {
+		e.status = "Nothing to undo"
+		return
+	}
+	e.redo = append(e.redo, e.level)
+	e.level = e.undo[len(e.undo)-1]
+	e.undo = e.undo[:len(e.undo)-1]
+	e.dirty = true
+	e.rebuild()
+}
+
+// Redo re-applies the last undone change
+func (e *Editor) Redo() {
+	if len(e.redo) == 0 {
+		e.status = "Nothing to redo"
+		return
+	}
+	e.undo = append(e.undo, e.level)
+	e.level = e.redo[len(e.redo)-1]
+	e.redo = e.redo[:len(e.redo)-1]
+	e.dirty = true
+	e.rebuild()
+}
+
+// ToggleFormat converts the level between grid and pixel positioning
+func (e *Editor) ToggleFormat() {
+	e.checkpoint()
+	if e.level.UsePixelPositioning {
+		var dropped int
+		e.level, dropped = levels.ToGrid(e.level, levels.DefaultSpacingX, levels.DefaultSpacingY)
+		e.status = "Converted to grid positioning"
+		if dropped > 0 {
+			e.status += fmt.Sprintf(" (%d overlapping bricks dropped)", dropped)
+		}
+	} else {
+		converted := levels.ToPixel(e.level)
+		e.status = "Converted to pixel positioning"
+		if err := levels.VerifyMigration(e.level, converted); err != nil {
+			e.status += " (layout changed: " + levels.AsProblems(err)[0].Message + ")"
+		}
+		e.level = converted
+	}
+	e.selected = -1
+	e.rebuild()
+}
+
+// nearestCell finds the free grid cell whose on-screen centre, after row
+// centring, is closest to (x, y). exclude is a brick to ignore (the one being moved).
+func (e *Editor) nearestCell(x, y float64, exclude int) (int, int, bool) {
+	v := e.view()
+	strideY := float64(v.BrickHeight + v.BrickSpacingY)
+	if strideY <= 0 {
+		return 0, 0, false
+	}
+	row := clampInt(int((y-entities.GameAreaTop)/strideY), 0, entities.BrickRows-1)
+
+	var others []entities.LevelBrick
+	for i, lb := range v.Bricks {
+		if i != exclude && lb.Y == row {
+			others = append(others, lb)
+		}
+	}
+
+	bestX, bestDist := -1, math.Inf(1)
+	for cx := 0; cx < entities.BrickCols; cx++ {
+		candidate := entities.LevelBrick{X: cx, Y: row}
+		occupied := false
+		for _, lb := range others {
+			if lb.X == cx {
+				occupied = true
+				break
+			}
+		}
+		if occupied {
+			continue
+		}
+
+		rowMin, rowMax := levels.RowBounds(append(others, candidate))
+		brick := entities.NewBrickFromLevelWithBounds(candidate,
+			v.BrickWidth, v.BrickHeight, v.BrickSpacingX, v.BrickSpacingY, rowMin[row], rowMax[row])
+		left, _, right, _ := brick.GetBounds()
+		if d := math.Abs((left+right)/2 - x); d < bestDist {
+			bestX, bestDist = cx, d
+		}
+	}
+
+	return bestX, row, bestX >= 0
+}
+
+// Save validates the level and writes it to its level file
+func (e *Editor) Save() error {
+	if problems := levels.AsProblems(levels.ValidateLevel(e.view())); len(problems) > 0 {
+		e.status = "Not saved: " + problems[0].Message
+		if len(problems) > 1 {
+			e.status += fmt.Sprintf(" (and %d more problems)", len(problems)-1)
+		}
+		return problems
+	}
+	if err := e.pack.SaveLevel(e.levelNum, e.level); err != nil {
+		e.status = "Not saved: " + err.Error()
+		return err
+	}
+	e.dirty, e.discardArmed = false, false
+	e.status = fmt.Sprintf("Saved %s", e.pack.LevelName(e.levelNum))
+	return nil
+}
+
+// PaletteRect returns the screen rectangle of palette entry i
+func PaletteRect(i int) (x, y, w, h float64) {
+	return paletteX + float64(i)*(paletteW+paletteGap), paletteY, paletteW, paletteH
+}
+
+// PaletteIndexAt returns the palette entry at the point, or -1
+func (e *Editor) PaletteIndexAt(x, y float64) int {
+	for i := range e.palette {
+		px, py, pw, ph := PaletteRect(i)
+		if x >= px && x <= px+pw && y >= py && y <= py+ph {
+			return i
+		}
+	}
+	return -1
+}
+
+// setBrickType stores the type in the field the level format uses
+func setBrickType(lb *entities.LevelBrick, t string, pixel bool) {
+	if pixel {
+		lb.Type, lb.BrickType = t, ""
+	} else {
+		lb.Type, lb.BrickType = "", t
+	}
+}
+
+// clampPixel converts a top-left screen position to snapped gameplay-area coordinates
+func clampPixel(left, top, w, h float64) (float64, float64) {
+	x := clampInt(snap(left-entities.GameAreaLeft), 0, int(entities.GameAreaWidth-w))
+	y := clampInt(snap(top-entities.GameAreaTop), 0, int(entities.PaddleLaneTop-entities.GameAreaTop-h))
+	return float64(x), float64(y)
+}
+
+func snap(v float64) int {
+	return int(math.Round(v/pixelSnap)) * pixelSnap
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -1,5 +1,7 @@
 package entities
 
+import "math"
+
 const (
 	BallRadius = 12
 	HUDHeight  = 80
@@ -9,7 +11,10 @@ const (
 type Ball struct {
 	x, y   float64 // center position
 	vx, vy float64 // velocity
-	speed  float64 // configured speed for this ball
+	speed  float64 // authoritative speed (velocity magnitude); every bounce keeps |v| == speed
+
+	minSpeed, maxSpeed float64 // speed limits; 0 means unlimited
+	heldSpeed          float64 // speed to return to after a temporary change, 0 if none; see HoldSpeed
 }
 
 // NewBall creates a new ball positioned above the paddle with default speed
@@ -17,32 +22,34 @@ func NewBall() *Ball {
 	return NewBallWithSpeed(960) // doubled default speed
 }
 
-// NewBallWithSpeed creates a new ball with configurable speed positioned at gameplay area center
+// NewBallWithSpeed creates a new ball with configurable speed positioned at gameplay area center.
+// speed is the per-axis launch speed, so the ball travels diagonally at speed·√2.
 func NewBallWithSpeed(speed float64) *Ball {
 	return &Ball{
 		x:     GameAreaLeft + GameAreaWidth/2, // center of gameplay area
 		y:     PaddleY - 40,
 		vx:    speed,
 		vy:    -speed,
-		speed: speed,
+		speed: math.Hypot(speed, speed),
 	}
 }
 
-// NewBallAbovePaddle creates a new ball positioned above the paddle with configurable speed
+// NewBallAbovePaddle creates a new ball positioned above the paddle with configurable speed.
+// speed is the per-axis launch speed, so the ball travels diagonally at speed·√2.
 func NewBallAbovePaddle(paddleX float64, speed float64) *Ball {
 	return &Ball{
 		x:     paddleX, // position above the paddle's current location
 		y:     PaddleY - 40,
 		vx:    speed,
 		vy:    -speed,
-		speed: speed,
+		speed: math.Hypot(speed, speed),
 	}
 }
 
-// Update handles ball movement
-func (b *Ball) Update() {
-	b.x += b.vx * Tick
-	b.y += b.vy * Tick
+// Advance moves the ball along its velocity for dt seconds
+func (b *Ball) Advance(dt float64) {
+	b.x += b.vx * dt
+	b.y += b.vy * dt
 }
 
 // X returns the center X position of the ball
@@ -55,6 +62,12 @@ func (b *Ball) Y() float64 {
 	return b.y
 }
 
+// SetPosition moves the ball's center to x, y
+func (b *Ball) SetPosition(x, y float64) {
+	b.x = x
+	b.y = y
+}
+
 // VX returns the X velocity of the ball
 func (b *Ball) VX() float64 {
 	return b.vx
@@ -71,6 +84,73 @@ func (b *Ball) SetVelocity(vx, vy float64) {
 	b.vy = vy
 }
 
+// Speed returns the ball's authoritative speed in px/s
+func (b *Ball) Speed() float64 {
+	return b.speed
+}
+
+// SetSpeed changes the ball's speed, clamped to its limits, keeping its direction
+func (b *Ball) SetSpeed(speed float64) {
+	if b.maxSpeed > 0 && speed > b.maxSpeed {
+		speed = b.maxSpeed
+	}
+	if speed < b.minSpeed {
+		speed = b.minSpeed
+	}
+	b.speed = speed
+
+	mag := math.Hypot(b.vx, b.vy)
+	if mag == 0 {
+		b.vx, b.vy = 0, -speed // no direction yet: head straight up
+		return
+	}
+	b.vx *= speed / mag
+	b.vy *= speed / mag
+}
+
+// ScaleSpeed multiplies the ball's speed by factor, keeping its direction
+func (b *Ball) ScaleSpeed(factor float64) {
+	b.SetSpeed(b.speed * factor)
+}
+
+// HoldSpeed remembers the ball's current speed so RestoreSpeed can return to it
+// exactly after a temporary change. A speed already held is kept.
+func (b *Ball) HoldSpeed() {
+	if b.heldSpeed == 0 {
+		b.heldSpeed = b.speed
+	}
+}
+
+// RestoreSpeed returns the ball to the speed remembered by HoldSpeed and
+// forgets it. It reports false, leaving the ball alone, if no speed was held.
+func (b *Ball) RestoreSpeed() bool {
+	if b.heldSpeed == 0 {
+		return false
+	}
+	b.SetSpeed(b.heldSpeed)
+	b.heldSpeed = 0
+	return true
+}
+
+// SetSpeedLimits sets the minimum and maximum speed (0 for no maximum) and re-clamps the current speed
+func (b *Ball) SetSpeedLimits(minSpeed, maxSpeed float64) {
+	b.minSpeed = minSpeed
+	b.maxSpeed = maxSpeed
+	b.SetSpeed(b.speed)
+}
+
+// Clone returns an independent copy of the ball
+func (b *Ball) Clone() *Ball {
+	c := *b
+	return &c
+}
+
+// Rotate turns the ball's direction of travel by angle radians, keeping its speed
+func (b *Ball) Rotate(angle float64) {
+	sin, cos := math.Sincos(angle)
+	b.vx, b.vy = b.vx*cos-b.vy*sin, b.vx*sin+b.vy*cos
+}
+
 // ReverseX reverses the ball's X velocity
 func (b *Ball) ReverseX() {
 	b.vx = -b.vx
@@ -91,7 +171,7 @@ func (b *Ball) IsLost() bool {
 	return b.y > GameAreaBottom+100 // below gameplay area
 }
 
-// GetBounds returns the ball's bounding box for collision detection
+// GetBounds returns the ball's bounding box
 func (b *Ball) GetBounds() (left, top, right, bottom float64) {
 	left = b.x - BallRadius
 	right = b.x + BallRadius
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"image/color"
+
+	"BRIX/config"
 )
 
 const (
@@ -9,16 +11,14 @@ const (
 	BrickRows = 10
 )
 
-// BrickType represents the type of brick with direct sprite mapping
+// BrickType is the key of a brick type in config.Brick, which defines its
+// sprite, colour, scores and behaviours
 type BrickType string
 
-const (
-	BrickTypeStandard BrickType = "standard" // brick-standard.png
-	BrickTypeTusi     BrickType = "tusi"     // brick-tusi.png
-	BrickTypeWeed     BrickType = "weed"     // brick-weed.png
-	BrickTypeColumbia BrickType = "columbia" // brick-columbia.png
-	BrickTypeSupreme  BrickType = "supreme"  // brick-supreme.png
-)
+// Config returns the type's entry in brick_types.json
+func (t BrickType) Config() config.BrickTypeCfg {
+	return config.Brick[string(t)]
+}
 
 // Brick represents a single brick in the level
 type Brick struct {
@@ -31,6 +31,7 @@ type Brick struct {
 
 	brickType BrickType // type of brick (maps directly to sprite)
 	hits      int       // hits required to destroy
+	startHits int       // hits the brick was built with, for showing damage
 	active    bool      // whether brick is still active
 
 	// Level-specific sizing (set when brick is created)
@@ -44,14 +45,14 @@ type Brick struct {
 // LevelBrick represents a brick definition from level data
 type LevelBrick struct {
 	// Grid-based positioning (legacy)
-	X         int    `json:"x"`
-	Y         int    `json:"y"`
+	X         int    `json:"x,omitempty"`
+	Y         int    `json:"y,omitempty"`
 	BrickType string `json:"bricktype,omitempty"` // legacy field name
 
 	// Pixel-perfect positioning (new format)
-	PixelX int    `json:"pixel_x,omitempty"` // absolute pixel X position
-	PixelY int    `json:"pixel_y,omitempty"` // absolute pixel Y position
-	Type   string `json:"type,omitempty"`    // unified type field
+	PixelX float64 `json:"pixel_x,omitempty"` // pixel X position within the gameplay area
+	PixelY float64 `json:"pixel_y,omitempty"` // pixel Y position within the gameplay area
+	Type   string  `json:"type,omitempty"`    // unified type field
 
 	// Common fields
 	Hits   int `json:"hits"`
@@ -66,6 +67,7 @@ func NewBrick(x, y int, brickType BrickType, hits int, width, height, spacingX,
 		y:         y,
 		brickType: brickType,
 		hits:      hits,
+		startHits: hits,
 		active:    true,
 		width:     width,
 		height:    height,
@@ -83,6 +85,7 @@ func NewBrickFromLevel(levelBrick LevelBrick, width, height, spacingX, spacingY
 		y:         levelBrick.Y,
 		brickType: ParseBrickType(levelBrick.BrickType),
 		hits:      levelBrick.Hits,
+		startHits: levelBrick.Hits,
 		active:    true,
 		width:     width,
 		height:    height,
@@ -100,6 +103,7 @@ func NewBrickFromLevelWithBounds(levelBrick LevelBrick, width, height, spacingX,
 		y:         levelBrick.Y,
 		brickType: ParseBrickType(levelBrick.BrickType),
 		hits:      levelBrick.Hits,
+		startHits: levelBrick.Hits,
 		active:    true,
 		width:     width,
 		height:    height,
@@ -118,69 +122,76 @@ func NewBrickPixelPosition(pixelX, pixelY float64, brickType BrickType, hits, wi
 		usePixelPosition: true,
 		brickType:        brickType,
 		hits:             hits,
+		startHits:        hits,
 		active:           true,
 		width:            width,
 		height:           height,
 	}
 }
 
-// NewBrickFromLevelPixel creates a brick from pixel-perfect level data
-func NewBrickFromLevelPixel(levelBrick LevelBrick, defaultWidth, defaultHeight int) *Brick {
-	// Determine type from either "type" or "bricktype" field
-	brickTypeStr := levelBrick.Type
-	if brickTypeStr == "" {
-		brickTypeStr = levelBrick.BrickType
+// TypeName returns the brick's type name from either the "type" or "bricktype" field
+func (lb LevelBrick) TypeName() string {
+	if lb.Type != "" {
+		return lb.Type
 	}
+	return lb.BrickType
+}
 
+// PixelBounds returns a pixel-format brick's position within the gameplay area
+// and its size, falling back to the level's default size
+func (lb LevelBrick) PixelBounds(defaultWidth, defaultHeight int) (x, y float64, width, height int) {
 	// Use per-brick dimensions or defaults
-	width := levelBrick.Width
+	width = lb.Width
 	if width == 0 {
 		width = defaultWidth
 	}
-	height := levelBrick.Height
+	height = lb.Height
 	if height == 0 {
 		height = defaultHeight
 	}
 
-	// Use pixel position if available, otherwise convert from grid
-	var pixelX, pixelY float64
-	if levelBrick.PixelX != 0 || levelBrick.PixelY != 0 {
-		pixelX = float64(levelBrick.PixelX)
-		pixelY = float64(levelBrick.PixelY)
-	} else {
-		// Convert from grid coordinates using default spacing
-		pixelX = float64(levelBrick.X)
-		pixelY = float64(levelBrick.Y)
+	// Use pixel position if available, otherwise fall back to the x/y fields
+	if lb.PixelX != 0 || lb.PixelY != 0 {
+		return lb.PixelX, lb.PixelY, width, height
 	}
+	return float64(lb.X), float64(lb.Y), width, height
+}
+
+// NewBrickFromLevelPixel creates a brick from pixel-perfect level data
+func NewBrickFromLevelPixel(levelBrick LevelBrick, defaultWidth, defaultHeight int) *Brick {
+	x, y, width, height := levelBrick.PixelBounds(defaultWidth, defaultHeight)
 
 	return &Brick{
-		pixelX:           pixelX,
-		pixelY:           pixelY,
+		pixelX:           x,
+		pixelY:           y,
 		usePixelPosition: true,
-		brickType:        ParseBrickType(brickTypeStr),
+		brickType:        ParseBrickType(levelBrick.TypeName()),
 		hits:             levelBrick.Hits,
+		startHits:        levelBrick.Hits,
 		active:           true,
 		width:            width,
 		height:           height,
 	}
 }
 
-// ParseBrickType converts a string to a BrickType (for backward compatibility)
+// ParseBrickType resolves a type name or alias from level data to its brick
+// type. Unknown names are kept as they are; level validation reports them.
 func ParseBrickType(typeStr string) BrickType {
-	switch typeStr {
-	case "columbia", "white":
-		return BrickTypeColumbia
-	case "supreme", "pink", "purple":
-		return BrickTypeSupreme
-	case "tusi", "green":
-		return BrickTypeTusi
-	case "weed", "blue", "cyan":
-		return BrickTypeWeed
-	case "standard", "default", "red", "orange", "yellow":
-		return BrickTypeStandard
-	default:
-		return BrickTypeStandard
+	if t, ok := LookupBrickType(typeStr); ok {
+		return t
 	}
+	return BrickType(typeStr)
+}
+
+// LookupBrickType resolves a type name or alias from level data, reporting whether the name is known
+func LookupBrickType(typeStr string) (BrickType, bool) {
+	key, ok := config.LookupBrickType(typeStr)
+	return BrickType(key), ok
+}
+
+// BrickTypeNames returns every accepted brick type name, aliases included, in sorted order
+func BrickTypeNames() []string {
+	return config.BrickTypeNames()
 }
 
 // X returns the grid X position
@@ -208,9 +219,28 @@ func (b *Brick) IsActive() bool {
 	return b.active
 }
 
+// StartHits returns the hits the brick was built with
+func (b *Brick) StartHits() int {
+	return b.startHits
+}
+
+// Damage returns the fraction of its starting hits the brick has lost, from 0
+// for an untouched brick up to, but never reaching, 1
+func (b *Brick) Damage() float64 {
+	if b.startHits <= 0 || b.hits >= b.startHits {
+		return 0
+	}
+	return float64(b.startHits-b.hits) / float64(b.startHits)
+}
+
+// Breakable reports whether the brick can be destroyed, i.e. its type isn't unbreakable
+func (b *Brick) Breakable() bool {
+	return !b.brickType.Config().Has(config.BehaviourUnbreakable)
+}
+
 // Hit reduces the brick's hit count and deactivates it if necessary
 func (b *Brick) Hit() bool {
-	if !b.active {
+	if !b.active || !b.Breakable() {
 		return false
 	}
 
@@ -259,22 +289,9 @@ func (b *Brick) GetBounds() (left, top, right, bottom float64) {
 	return
 }
 
-// GetDisplayColor returns the appropriate color for rendering based on the brick type
+// GetDisplayColor returns the brick type's colour from brick_types.json, grey for unknown types
 func (b *Brick) GetDisplayColor() color.Color {
-	switch b.brickType {
-	case BrickTypeStandard:
-		return color.RGBA{255, 100, 100, 255} // red-ish standard
-	case BrickTypeTusi:
-		return color.RGBA{255, 140, 255, 255} // tusi pinkish
-	case BrickTypeWeed:
-		return color.RGBA{100, 255, 100, 255} // green weed
-	case BrickTypeColumbia:
-		return color.RGBA{255, 255, 255, 255} // white
-	case BrickTypeSupreme:
-		return color.RGBA{255, 100, 255, 255} // pink/purple supreme
-	default:
-		return color.RGBA{200, 200, 200, 255} // gray default
-	}
+	return b.brickType.Config().DisplayColor()
 }
 
 // Width returns the brick's width
@@ -0,0 +1,68 @@
+package entities
+
+const (
+	CapsuleWidth  = 60.0
+	CapsuleHeight = 24.0
+	CapsuleSpeed  = 240.0 // px/s fall speed
+)
+
+// Capsule is a falling power-up released by a destroyed brick
+type Capsule struct {
+	x, y   float64 // center position
+	kind   string  // power-up name from brick_types.json
+	active bool    // false once caught or lost
+}
+
+// NewCapsule creates a capsule for the given power-up centred at x, y
+func NewCapsule(x, y float64, kind string) *Capsule {
+	return &Capsule{
+		x:      x,
+		y:      y,
+		kind:   kind,
+		active: true,
+	}
+}
+
+// Update moves the capsule down by one tick
+func (c *Capsule) Update() {
+	c.y += CapsuleSpeed * Tick
+}
+
+// X returns the center X position of the capsule
+func (c *Capsule) X() float64 {
+	return c.x
+}
+
+// Y returns the center Y position of the capsule
+func (c *Capsule) Y() float64 {
+	return c.y
+}
+
+// Kind returns the power-up name carried by the capsule
+func (c *Capsule) Kind() string {
+	return c.kind
+}
+
+// IsActive returns whether the capsule is still falling
+func (c *Capsule) IsActive() bool {
+	return c.active
+}
+
+// Deactivate removes the capsule from play
+func (c *Capsule) Deactivate() {
+	c.active = false
+}
+
+// IsLost returns true if the capsule has fallen off the bottom of the gameplay area
+func (c *Capsule) IsLost() bool {
+	return c.y-CapsuleHeight/2 > GameAreaBottom
+}
+
+// GetBounds returns the capsule's bounding box for collision detection
+func (c *Capsule) GetBounds() (left, top, right, bottom float64) {
+	left = c.x - CapsuleWidth/2
+	right = c.x + CapsuleWidth/2
+	top = c.y - CapsuleHeight/2
+	bottom = c.y + CapsuleHeight/2
+	return
+}
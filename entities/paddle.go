@@ -1,9 +1,5 @@
 package entities
 
-import (
-	"github.com/hajimehoshi/ebiten/v2"
-)
-
 const Tick = 1.0 / 60.0 // fixed timestep (should match ebiten TPS)
 
 var (
@@ -14,7 +10,8 @@ var (
 	PaddleFriction = 4800.0 // px/s² when no key
 	PaddleMaxSpeed = 900.0  // px/s terminal velocity (further reduced)
 
-	PaddleY = 960.0 // Y position
+	PaddleY       = 960.0          // Y position
+	PaddleLaneTop = PaddleY - 80.0 // bricks must stay above this so the ball has room to launch
 
 	// Gameplay area boundaries (20 px border on sides & bottom, 60 px HUD on top)
 	GameAreaLeft   = 20.0 // left border
@@ -29,29 +26,31 @@ var (
 
 // Paddle represents the player's paddle
 type Paddle struct {
-	x  float64 // center position
-	vx float64 // horizontal velocity
+	x     float64 // center position
+	vx    float64 // horizontal velocity
+	width float64 // current width; power-ups may change it
 }
 
 // NewPaddle creates a new paddle at the center of the gameplay area
 func NewPaddle() *Paddle {
 	return &Paddle{
-		x:  GameAreaLeft + GameAreaWidth/2, // center of gameplay area
-		vx: 0,
+		x:     GameAreaLeft + GameAreaWidth/2, // center of gameplay area
+		vx:    0,
+		width: PaddleWidth,
 	}
 }
 
 // Update applies acceleration, friction, and updates position – gives the paddle inertia.
-func (p *Paddle) Update() {
+// move is the input axis in the range -1 (full left) .. 1 (full right).
+func (p *Paddle) Update(move float64) {
 	// 1. Determine acceleration from input
-	ax := 0.0
-	if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
-		ax = -PaddleAccel
+	if move > 1 {
+		move = 1
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
-		// If both keys held ax cancels to 0 → friction only
-		ax = +PaddleAccel
+	if move < -1 {
+		move = -1
 	}
+	ax := move * PaddleAccel
 
 	// 2. If no input apply friction opposite to current velocity
 	if ax == 0 {
@@ -81,14 +80,22 @@ func (p *Paddle) Update() {
 	p.x += p.vx * Tick
 
 	// 5. Collision with gameplay area edges – stop and zero velocity
-	if p.x < GameAreaLeft+PaddleWidth/2 {
-		p.x = GameAreaLeft + PaddleWidth/2
+	if p.clamp() {
 		p.vx = 0
 	}
-	if p.x > GameAreaRight-PaddleWidth/2 {
-		p.x = GameAreaRight - PaddleWidth/2
-		p.vx = 0
+}
+
+// clamp keeps the paddle inside the gameplay area and reports whether it moved it
+func (p *Paddle) clamp() bool {
+	if p.x < GameAreaLeft+p.width/2 {
+		p.x = GameAreaLeft + p.width/2
+		return true
+	}
+	if p.x > GameAreaRight-p.width/2 {
+		p.x = GameAreaRight - p.width/2
+		return true
 	}
+	return false
 }
 
 // X returns the center X position of the paddle
@@ -103,7 +110,13 @@ func (p *Paddle) Y() float64 {
 
 // Width returns the width of the paddle
 func (p *Paddle) Width() float64 {
-	return PaddleWidth
+	return p.width
+}
+
+// SetWidth resizes the paddle around its centre, keeping it inside the gameplay area
+func (p *Paddle) SetWidth(width float64) {
+	p.width = width
+	p.clamp()
 }
 
 // Height returns the height of the paddle
@@ -113,8 +126,8 @@ func (p *Paddle) Height() float64 {
 
 // GetBounds returns the paddle's bounding box for collision detection
 func (p *Paddle) GetBounds() (left, top, right, bottom float64) {
-	left = p.x - PaddleWidth/2
-	right = p.x + PaddleWidth/2
+	left = p.x - p.width/2
+	right = p.x + p.width/2
 	top = PaddleY
 	bottom = PaddleY + PaddleHeight
 	return
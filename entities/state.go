@@ -0,0 +1,78 @@
+package entities
+
+// The *State types are plain copies of the entities' unexported fields so a
+// game in progress can be serialised and restored exactly.
+
+// BrickState is the part of a brick that changes during play; its position and
+// size come from the level it was built from
+type BrickState struct {
+	Hits   int  `json:"hits"`
+	Active bool `json:"active"`
+}
+
+// State returns the brick's remaining hits and whether it is still in play
+func (b *Brick) State() BrickState {
+	return BrickState{Hits: b.hits, Active: b.active}
+}
+
+// SetState restores the brick's remaining hits and active flag
+func (b *Brick) SetState(s BrickState) {
+	b.hits = s.Hits
+	b.active = s.Active
+}
+
+// BallState holds a ball's position, velocity and speed settings
+type BallState struct {
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	VX       float64 `json:"vx"`
+	VY       float64 `json:"vy"`
+	Speed    float64 `json:"speed"`
+	MinSpeed float64 `json:"min_speed"`
+	MaxSpeed float64 `json:"max_speed"`
+	Held     float64 `json:"held_speed,omitempty"` // speed to restore when a slow-down ends
+}
+
+// State returns a copy of the ball's fields
+func (b *Ball) State() BallState {
+	return BallState{X: b.x, Y: b.y, VX: b.vx, VY: b.vy, Speed: b.speed, MinSpeed: b.minSpeed, MaxSpeed: b.maxSpeed, Held: b.heldSpeed}
+}
+
+// NewBallFromState recreates a ball exactly as it was saved
+func NewBallFromState(s BallState) *Ball {
+	return &Ball{x: s.X, y: s.Y, vx: s.VX, vy: s.VY, speed: s.Speed, minSpeed: s.MinSpeed, maxSpeed: s.MaxSpeed, heldSpeed: s.Held}
+}
+
+// PaddleState holds the paddle's position, velocity and current width
+type PaddleState struct {
+	X     float64 `json:"x"`
+	VX    float64 `json:"vx"`
+	Width float64 `json:"width"`
+}
+
+// State returns a copy of the paddle's fields
+func (p *Paddle) State() PaddleState {
+	return PaddleState{X: p.x, VX: p.vx, Width: p.width}
+}
+
+// SetState restores the paddle's position, velocity and width
+func (p *Paddle) SetState(s PaddleState) {
+	p.x, p.vx, p.width = s.X, s.VX, s.Width
+}
+
+// CapsuleState holds a falling capsule's position and power-up
+type CapsuleState struct {
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	Kind string  `json:"kind"`
+}
+
+// State returns a copy of the capsule's fields
+func (c *Capsule) State() CapsuleState {
+	return CapsuleState{X: c.x, Y: c.y, Kind: c.kind}
+}
+
+// NewCapsuleFromState recreates a falling capsule as it was saved
+func NewCapsuleFromState(s CapsuleState) *Capsule {
+	return NewCapsule(s.X, s.Y, s.Kind)
+}
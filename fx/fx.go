@@ -0,0 +1,231 @@
+// Package fx runs the game's purely cosmetic effects: debris from destroyed
+// bricks, sparks off the paddle, ball trails and floating score popups. It is
+// driven by the simulation's events and never feeds anything back into it, so
+// effects can be switched off without changing how a game or replay plays out.
+package fx
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"BRIX/entities"
+	"BRIX/sim"
+)
+
+// Capacity of the particle and popup pools; effects beyond them are dropped
+const (
+	MaxParticles = 600
+	MaxPopups    = 32
+)
+
+// gravity pulls debris and sparks down, in px/s²
+const gravity = 900
+
+// Kind is what a particle is, which decides how it is drawn
+type Kind int
+
+const (
+	Debris Kind = iota // a square chip of a destroyed brick
+	Spark              // a small bright dot thrown off the paddle
+	Trail              // a fading copy of a ball
+)
+
+// Particle is one live particle. Age and Life are in seconds.
+type Particle struct {
+	Kind   Kind
+	X, Y   float64
+	VX, VY float64
+	Size   float64
+	Color  color.RGBA
+	Age    float64
+	Life   float64
+}
+
+// Fade returns how much of the particle's opacity is left, from 1 down to 0
+func (p Particle) Fade() float64 {
+	return 1 - p.Age/p.Life
+}
+
+// Popup is a score that floats up from where it was earned
+type Popup struct {
+	X, Y float64
+	Text string
+	Age  float64
+	Life float64
+}
+
+// Fade returns how much of the popup's opacity is left, from 1 down to 0
+func (p Popup) Fade() float64 {
+	return 1 - p.Age/p.Life
+}
+
+// Emitter spawns, advances and recycles effects. Particles live in a fixed
+// pool allocated up front, so a busy level doesn't allocate every frame.
+type Emitter struct {
+	enabled   bool
+	particles []Particle // the first live entries are in use
+	live      int
+	popups    []Popup
+
+	rng *rand.Rand // separate from the simulation's so effects never change a run
+}
+
+// New creates an emitter; a disabled one ignores events until enabled
+func New(enabled bool) *Emitter {
+	return &Emitter{
+		enabled:   enabled,
+		particles: make([]Particle, MaxParticles),
+		popups:    make([]Popup, 0, MaxPopups),
+		rng:       rand.New(rand.NewSource(1)),
+	}
+}
+
+// Enabled reports whether effects are shown
+func (e *Emitter) Enabled() bool {
+	return e.enabled
+}
+
+// SetEnabled switches effects on or off; switching off clears those in flight
+func (e *Emitter) SetEnabled(enabled bool) {
+	e.enabled = enabled
+	if !enabled {
+		e.Clear()
+	}
+}
+
+// Clear removes every effect, e.g. when a level ends
+func (e *Emitter) Clear() {
+	e.live = 0
+	e.popups = e.popups[:0]
+}
+
+// Particles returns the live particles. The slice is only valid until the next Update.
+func (e *Emitter) Particles() []Particle {
+	return e.particles[:e.live]
+}
+
+// Popups returns the score popups on screen
+func (e *Emitter) Popups() []Popup {
+	return e.popups
+}
+
+// Handle spawns the effects for a tick's events
+func (e *Emitter) Handle(events []sim.Event) {
+	if !e.enabled {
+		return
+	}
+	for _, ev := range events {
+		switch ev.Kind {
+		case sim.EventBrickDestroyed:
+			e.debris(ev.Brick)
+			e.popup(ev.Brick, ev.Points)
+		case sim.EventBrickHit:
+			e.popup(ev.Brick, ev.Points)
+		case sim.EventPaddleHit:
+			e.sparks(ev.X, ev.Y)
+		}
+	}
+}
+
+// Trail leaves a fading copy of each ball where it is now; call it once per tick
+func (e *Emitter) Trail(balls []*entities.Ball) {
+	if !e.enabled {
+		return
+	}
+	for _, b := range balls {
+		e.spawn(Particle{Kind: Trail, X: b.X(), Y: b.Y(), Size: b.Radius() * 0.8,
+			Color: color.RGBA{255, 255, 255, 255}, Life: 0.15})
+	}
+}
+
+// Update advances every effect by one entities.Tick and recycles finished ones
+func (e *Emitter) Update() {
+	const dt = entities.Tick
+	for i := 0; i < e.live; {
+		p := &e.particles[i]
+		if p.Age += dt; p.Age >= p.Life {
+			// Swap the last live particle into this slot and look at it next
+			e.live--
+			e.particles[i] = e.particles[e.live]
+			continue
+		}
+		if p.Kind != Trail {
+			p.VY += gravity * dt
+		}
+		p.X += p.VX * dt
+		p.Y += p.VY * dt
+		i++
+	}
+
+	kept := e.popups[:0]
+	for _, p := range e.popups {
+		if p.Age += dt; p.Age < p.Life {
+			p.Y -= 60 * dt
+			kept = append(kept, p)
+		}
+	}
+	e.popups = kept
+}
+
+// spawn adds a particle to the pool, dropping it if the pool is full
+func (e *Emitter) spawn(p Particle) {
+	if e.live == len(e.particles) {
+		return
+	}
+	e.particles[e.live] = p
+	e.live++
+}
+
+// debris bursts a destroyed brick into chips of its colour
+func (e *Emitter) debris(b *entities.Brick) {
+	left, top, right, bottom := b.GetBounds()
+	tint := color.RGBAModel.Convert(b.GetDisplayColor()).(color.RGBA)
+	for i := 0; i < 14; i++ {
+		angle := e.rng.Float64() * 2 * math.Pi
+		speed := 100 + e.rng.Float64()*200
+		e.spawn(Particle{
+			Kind:  Debris,
+			X:     left + e.rng.Float64()*(right-left),
+			Y:     top + e.rng.Float64()*(bottom-top),
+			VX:    math.Cos(angle) * speed,
+			VY:    math.Sin(angle)*speed - 150,
+			Size:  3 + e.rng.Float64()*3,
+			Color: tint,
+			Life:  0.5 + e.rng.Float64()*0.4,
+		})
+	}
+}
+
+// sparks throws a spray of sparks up from where the ball met the paddle
+func (e *Emitter) sparks(x, y float64) {
+	for i := 0; i < 8; i++ {
+		angle := -math.Pi/2 + (e.rng.Float64()-0.5)*math.Pi*0.6
+		speed := 200 + e.rng.Float64()*200
+		e.spawn(Particle{
+			Kind:  Spark,
+			X:     x,
+			Y:     y,
+			VX:    math.Cos(angle) * speed,
+			VY:    math.Sin(angle) * speed,
+			Size:  2,
+			Color: color.RGBA{255, 230, 140, 255},
+			Life:  0.25 + e.rng.Float64()*0.15,
+		})
+	}
+}
+
+// popup floats the points a brick earned up from its centre
+func (e *Emitter) popup(b *entities.Brick, points int) {
+	if points <= 0 || len(e.popups) == MaxPopups {
+		return
+	}
+	left, top, right, bottom := b.GetBounds()
+	e.popups = append(e.popups, Popup{
+		X:    (left + right) / 2,
+		Y:    (top + bottom) / 2,
+		Text: fmt.Sprintf("+%d", points),
+		Life: 0.8,
+	})
+}
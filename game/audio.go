@@ -0,0 +1,116 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/audio"
+	"BRIX/settings"
+	"BRIX/sim"
+	"BRIX/themes"
+)
+
+// volumeStep is how much [ and ] change the master volume
+const volumeStep = 0.1
+
+// loadSettings reads the player's settings, remembering where to save changes
+func (g *Game) loadSettings() {
+	g.settings = settings.Default()
+	path, err := settings.DefaultPath()
+	if err == nil {
+		g.settings, err = settings.Load(path)
+	}
+	if err != nil {
+		// A damaged file is left for the player to fix rather than overwritten
+		log.Printf("Using default settings, which will not be saved: %v", err)
+	} else {
+		g.settingsPath = path
+	}
+}
+
+// queueSounds collects the sounds for a world's events; they play once per frame
+func (g *Game) queueSounds(events []sim.Event) {
+	for _, e := range events {
+		var s audio.Sound
+		switch e.Kind {
+		case sim.EventPaddleHit:
+			s.Effect = audio.PaddleHit
+		case sim.EventWallBounce:
+			s.Effect = audio.WallBounce
+		case sim.EventBrickHit:
+			s = audio.Sound{Effect: audio.BrickHit, Brick: e.Brick.Type()}
+		case sim.EventBrickDestroyed:
+			s = audio.Sound{Effect: audio.BrickDestroy, Brick: e.Brick.Type()}
+		case sim.EventLifeLost:
+			s.Effect = audio.LifeLost
+		case sim.EventLevelComplete:
+			s.Effect = audio.LevelComplete
+		default:
+			continue
+		}
+		g.sounds = append(g.sounds, s)
+	}
+}
+
+// updateAudio plays the frame's sounds and keeps the current level's music playing during a run
+func (g *Game) updateAudio() {
+	g.audio.Play(g.sounds...)
+	g.sounds = g.sounds[:0]
+
+	w := g.activeWorld()
+	playing := g.mode == modePlay || g.mode == modePlaytest
+	if !playing || w.State() == sim.StateStart || w.Finished() {
+		g.audio.SetMusic(nil, "")
+		return
+	}
+	// A level's own music wins over its theme's
+	if level := w.Level(); level.Music != "" && w.Pack() != nil {
+		g.audio.SetMusic(w.Pack(), level.Music)
+	} else if theme := themes.Get(level.Theme); theme.Music != "" {
+		g.audio.SetMusic(theme, theme.Music)
+	} else {
+		g.audio.SetMusic(nil, "")
+	}
+}
+
+// updateVolumeKeys handles M to mute and [ and ] to change the volume, saving the change
+func (g *Game) updateVolumeKeys() {
+	s := &g.settings
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyM):
+		s.Muted = !s.Muted
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft):
+		s.AdjustVolume(-volumeStep)
+		s.Muted = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketRight):
+		s.AdjustVolume(volumeStep)
+		s.Muted = false
+	default:
+		return
+	}
+	g.audio.SetSettings(*s)
+
+	switch {
+	case !g.audio.Enabled():
+		g.showNotice("No audio device")
+	case s.Muted:
+		g.showNotice("Sound muted")
+	default:
+		g.showNotice(fmt.Sprintf("Volume %.0f%%", s.Volume*100))
+	}
+
+	g.saveSettings()
+}
+
+// saveSettings writes the player's settings, if they can be saved
+func (g *Game) saveSettings() {
+	if g.settingsPath == "" {
+		return
+	}
+	if err := settings.Save(g.settingsPath, g.settings); err != nil {
+		log.Printf("Failed to save settings: %v", err)
+	}
+}
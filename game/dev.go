@@ -0,0 +1,192 @@
+package game
+
+import (
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"BRIX/config"
+	"BRIX/levels"
+	"BRIX/themes"
+	"BRIX/watch"
+)
+
+// devPollTicks is how often development mode checks for changed files (every half second)
+const devPollTicks = 30
+
+// Directories development mode watches and reloads from
+var (
+	devLevelDirs  = []string{"levels"}
+	devSpriteDirs = []string{"assets"}
+)
+
+// devReload watches the level, config and sprite files in development mode and
+// applies changes to the running game
+type devReload struct {
+	watcher    *watch.Watcher
+	configDirs []string // -config overrides, reapplied on every config reload
+	ticks      int
+
+	levelPending bool // a level changed outside modePlay; reload it once play resumes
+
+	levelDirs, cfgDirs []string
+	errors             map[string]string // latest error per reload kind, shown on the overlay
+}
+
+// startDev begins watching for changes and loads sprites from disk so edits to them show up
+func (g *Game) startDev(configDirs []string) {
+	d := &devReload{
+		configDirs: configDirs,
+		levelDirs:  append([]string(nil), devLevelDirs...),
+		errors:     make(map[string]string),
+	}
+	if g.pack != nil && g.pack.Writable() {
+		d.levelDirs = append(d.levelDirs, g.pack.Source())
+	}
+	for _, l := range config.Layers(configDirs...) {
+		if l.Name != config.DefaultsSource {
+			d.cfgDirs = append(d.cfgDirs, l.Name)
+		}
+	}
+
+	roots := append(append(append([]string(nil), d.levelDirs...), d.cfgDirs...), devSpriteDirs...)
+	d.watcher = watch.New(roots...)
+	g.dev = d
+	g.reloadSprites()
+	log.Printf("Development mode: watching %s", strings.Join(roots, ", "))
+}
+
+// updateDev polls for changed files and reloads what they affect
+func (g *Game) updateDev() {
+	d := g.dev
+	if d == nil {
+		return
+	}
+	if d.ticks++; d.ticks < devPollTicks {
+		return
+	}
+	d.ticks = 0
+
+	var configChanged, levelsChanged, spritesChanged bool
+	for _, path := range d.watcher.Poll() {
+		configChanged = configChanged || under(path, d.cfgDirs)
+		levelsChanged = levelsChanged || under(path, d.levelDirs)
+		spritesChanged = spritesChanged || under(path, devSpriteDirs)
+	}
+
+	if configChanged {
+		g.reloadConfig()
+	}
+	if levelsChanged || d.levelPending {
+		g.reloadLevel()
+	}
+	if spritesChanged {
+		g.reloadSprites()
+	}
+}
+
+// reloadConfig re-runs config.Load; on failure the previous config stays in use.
+// Themes and the current level are checked again, since both name brick types.
+func (g *Game) reloadConfig() {
+	if err := config.Load(g.dev.configDirs...); err != nil {
+		g.dev.fail("config", err)
+		return
+	}
+	g.dev.ok("config")
+	log.Printf("Config reloaded")
+
+	if err := themes.Load(); err != nil {
+		g.dev.fail("themes", err)
+	} else {
+		g.dev.ok("themes")
+	}
+	if err := levels.ValidateLevel(g.world.Level()); err != nil {
+		g.dev.fail("level", err)
+	} else {
+		g.dev.ok("level")
+	}
+
+	// Brick types may have been added or given new sprites
+	g.reloadSprites()
+}
+
+// reloadLevel reopens the pack and reloads the level being played, keeping the
+// paddle where it is. Changes made while the editor, a playtest or another
+// screen is open wait until the game is back.
+func (g *Game) reloadLevel() {
+	if g.mode != modePlay {
+		g.dev.levelPending = true
+		return
+	}
+	g.dev.levelPending = false
+	if g.player != nil || g.world.Pack() == nil {
+		return // replays keep their own copy of the level
+	}
+
+	var pack *levels.Pack
+	var err error
+	if g.world.Pack().ID() == levels.BuiltinPackID {
+		pack, err = levels.BuiltinPack()
+	} else {
+		pack, err = levels.OpenPackPath(g.world.Pack().Source())
+	}
+	if err == nil {
+		err = g.world.ReloadLevel(pack)
+	}
+	if err != nil {
+		g.dev.fail("level", err)
+		return
+	}
+	g.pack = pack
+	g.dev.ok("level")
+}
+
+// reloadSprites rebuilds the images from the sprite directory, keeping the old ones on failure
+func (g *Game) reloadSprites() {
+	if err := g.renderer.ReloadImages(devSpriteDirs[0]); err != nil {
+		g.dev.fail("sprites", err)
+		return
+	}
+	g.dev.ok("sprites")
+}
+
+func (d *devReload) fail(kind string, err error) {
+	d.errors[kind] = err.Error()
+	log.Printf("Reloading %s failed: %v", kind, err)
+}
+
+func (d *devReload) ok(kind string) {
+	delete(d.errors, kind)
+}
+
+// errorLines returns the current reload errors for the overlay
+func (d *devReload) errorLines() []string {
+	if d == nil || len(d.errors) == 0 {
+		return nil
+	}
+	kinds := make([]string, 0, len(d.errors))
+	for kind := range d.errors {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var lines []string
+	for _, kind := range kinds {
+		lines = append(lines, "Reloading "+kind+" failed:")
+		for _, line := range strings.Split(d.errors[kind], "\n") {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
+
+// under reports whether path is inside any of dirs
+func under(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if watch.Under(filepath.Clean(path), filepath.Clean(dir)) {
+			return true
+		}
+	}
+	return false
+}
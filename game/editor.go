@@ -0,0 +1,176 @@
+package game
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/editor"
+	"BRIX/entities"
+	"BRIX/sim"
+)
+
+// editorDrag tracks a mouse drag in the editor
+type editorDrag struct {
+	brick    int     // brick being dragged, -1 for none
+	resizing bool    // dragging the resize handle rather than moving
+	offX     float64 // brick centre minus cursor at drag start
+	offY     float64
+}
+
+// digitKeys select palette entries 1-9
+var digitKeys = []ebiten.Key{
+	ebiten.KeyDigit1, ebiten.KeyDigit2, ebiten.KeyDigit3, ebiten.KeyDigit4, ebiten.KeyDigit5,
+	ebiten.KeyDigit6, ebiten.KeyDigit7, ebiten.KeyDigit8, ebiten.KeyDigit9,
+}
+
+// openEditor switches to the level editor on the current level
+func (g *Game) openEditor() {
+	if g.editor == nil {
+		g.editor = editor.New(g.world.Pack(), g.world.CurrentLevel())
+	}
+	g.drag = editorDrag{brick: -1}
+	g.mode = modeEdit
+}
+
+// closeEditor returns to the start screen, reloading the world so edits are picked up
+func (g *Game) closeEditor() {
+	g.editor = nil
+	g.world = sim.New(sim.Options{Pack: g.pack, StartLevel: g.world.CurrentLevel(), Seed: time.Now().UnixNano()})
+	g.mode = modePlay
+}
+
+// updateEditor handles editor keyboard shortcuts and mouse editing
+func (g *Game) updateEditor() {
+	e := g.editor
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	pressed := inpututil.IsKeyJustPressed
+
+	switch {
+	case ctrl && (pressed(ebiten.KeyY) || (shift && pressed(ebiten.KeyZ))):
+		e.Redo()
+	case ctrl && pressed(ebiten.KeyZ):
+		e.Undo()
+	case ctrl && pressed(ebiten.KeyS):
+		e.Save() // the editor reports the outcome in its status line
+	case pressed(ebiten.KeyEscape):
+		if e.ConfirmDiscard() {
+			g.closeEditor()
+		}
+		return
+	case pressed(ebiten.KeyP):
+		g.startPlaytest()
+		return
+	case pressed(ebiten.KeyPageUp) && e.LevelNum() > 1:
+		if e.ConfirmDiscard() {
+			e.Open(e.LevelNum() - 1)
+		}
+	case pressed(ebiten.KeyPageDown) && e.LevelNum() < e.MaxLevel():
+		if e.ConfirmDiscard() {
+			e.Open(e.LevelNum() + 1)
+		}
+	case pressed(ebiten.KeyG):
+		e.ToggleFormat()
+	case pressed(ebiten.KeyTab):
+		if shift {
+			e.CycleType(-1)
+		} else {
+			e.CycleType(1)
+		}
+	case pressed(ebiten.KeyEqual) || pressed(ebiten.KeyKPAdd):
+		e.AdjustHits(1)
+	case pressed(ebiten.KeyMinus) || pressed(ebiten.KeyKPSubtract):
+		e.AdjustHits(-1)
+	case pressed(ebiten.KeyDelete) || pressed(ebiten.KeyBackspace):
+		e.Delete(e.Selected())
+	}
+
+	for i, key := range digitKeys {
+		if pressed(key) {
+			e.SetType(i)
+		}
+	}
+
+	g.updateEditorMouse()
+}
+
+// updateEditorMouse selects, places, drags, resizes and deletes bricks with the mouse
+func (g *Game) updateEditorMouse() {
+	e := g.editor
+	cx, cy := ebiten.CursorPosition()
+	x, y := float64(cx), float64(cy)
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		e.Delete(e.BrickAt(x, y))
+		return
+	}
+
+	switch {
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+		if idx := e.PaletteIndexAt(x, y); idx >= 0 {
+			e.SetType(idx)
+			return
+		}
+		if y < entities.GameAreaTop {
+			return // clicks elsewhere in the HUD do nothing
+		}
+
+		i := e.BrickAt(x, y)
+		if i < 0 {
+			e.Select(-1)
+			e.Place(x, y)
+			return
+		}
+
+		e.Select(i)
+		left, top, right, bottom := e.Bricks()[i].GetBounds()
+		g.drag = editorDrag{
+			brick:    i,
+			resizing: e.OnResizeHandle(i, x, y),
+			offX:     (left+right)/2 - x,
+			offY:     (top+bottom)/2 - y,
+		}
+		e.BeginDrag()
+
+	case g.drag.brick >= 0 && ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft):
+		if g.drag.resizing {
+			e.DragResize(g.drag.brick, x, y)
+		} else {
+			e.DragMove(g.drag.brick, x+g.drag.offX, y+g.drag.offY)
+		}
+
+	case g.drag.brick >= 0:
+		e.EndDrag()
+		g.drag = editorDrag{brick: -1}
+	}
+}
+
+// startPlaytest plays the level open in the editor straight away
+func (g *Game) startPlaytest() {
+	g.playtest = sim.New(sim.Options{
+		Pack:         g.editor.Pack(),
+		StartLevel:   g.editor.LevelNum(),
+		Seed:         time.Now().UnixNano(),
+		Level:        g.editor.PlaytestLevel(),
+		StartPlaying: true,
+	})
+	g.mode = modePlaytest
+}
+
+// updatePlaytest runs the playtest until the player goes back or the level ends
+func (g *Game) updatePlaytest() {
+	a := g.input.Poll()
+	finished := g.playtest.State() == sim.StateLevelComplete || g.playtest.Finished()
+
+	if a.Back || (finished && a.Any()) {
+		g.playtest = nil
+		g.mode = modeEdit
+		return
+	}
+	g.updateVolumeKeys()
+	g.updateEffectsKey()
+	g.playtest.Step(a)
+	g.handleTick(g.playtest)
+}
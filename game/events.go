@@ -0,0 +1,49 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/sim"
+)
+
+// handleTick passes what happened during a world's last tick on to the audio,
+// renderer and effects
+func (g *Game) handleTick(w *sim.World) {
+	events := w.Events()
+	g.queueSounds(events)
+
+	// Effects only play over the playfield. They hold still while the game is
+	// paused or waiting for the next ball, and end with the level or the run.
+	switch w.State() {
+	case sim.StatePlaying:
+	case sim.StatePaused, sim.StateWaitingToContinue:
+		return
+	default:
+		g.fx.Clear()
+		g.renderer.ClearFlashes()
+		return
+	}
+	g.renderer.UpdateFlashes()
+	for _, e := range events {
+		if e.Kind == sim.EventBrickHit {
+			g.renderer.FlashBrick(e.Brick)
+		}
+	}
+	g.fx.Update()
+	g.fx.Handle(events)
+	g.fx.Trail(w.Balls())
+}
+
+// updateEffectsKey handles V to switch visual effects on and off, saving the choice
+func (g *Game) updateEffectsKey() {
+	if !inpututil.IsKeyJustPressed(ebiten.KeyV) {
+		return
+	}
+	g.settings.Effects = !g.settings.Effects
+	g.fx.SetEnabled(g.settings.Effects)
+	g.showNotice(fmt.Sprintf("Effects %s", map[bool]string{true: "on", false: "off"}[g.settings.Effects]))
+	g.saveSettings()
+}
@@ -2,42 +2,84 @@ package game
 
 import (
 	"log"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
-	"BRIX/entities"
+	"BRIX/audio"
+	"BRIX/editor"
+	"BRIX/fx"
+	"BRIX/input"
+	"BRIX/input/device"
 	"BRIX/levels"
-	"BRIX/physics"
 	"BRIX/render"
+	"BRIX/replay"
+	"BRIX/scores"
+	"BRIX/settings"
+	"BRIX/sim"
+	"BRIX/themes"
 )
 
-// GameState represents the current state of the game
-type GameState int
+// Options configures optional game features, usually from command-line flags
+type Options struct {
+	Dev        bool     // watch levels, config and sprites and reload them live
+	ConfigDirs []string // config override directories, reapplied when config reloads in dev mode
+	PackPath   string   // level pack directory or zip to play instead of the builtin levels
+	RecordPath string   // write a replay of the session here on Close
+	ReplayPath string   // play this replay back instead of reading devices
+	Sprites    string   // sprite pack directory or zip, overriding the one in the settings
+}
+
+// mode selects what the game adapter is currently driving
+type mode int
 
 const (
-	StateStart GameState = iota
-	StatePlaying
-	StatePaused
-	StateLevelComplete
-	StateWaitingToContinue
-	StateGameOver
+	modePlay      mode = iota // the normal game
+	modeEdit                  // the level editor
+	modePlaytest              // playing the level open in the editor
+	modePacks                 // choosing a level pack
+	modeNameEntry             // typing a name for a new high score
+	modeScores                // viewing the high-score table
 )
 
-// Game encapsulates the whole game world
+// Game adapts the headless sim.World to the ebiten.Game interface
 type Game struct {
-	paddle *entities.Paddle
-	ball   *entities.Ball
-	bricks []*entities.Brick
-	level  *levels.Level
+	world    *sim.World
+	input    input.InputSource
+	renderer *render.Renderer
 
-	currentLevel int
-	score        int
-	lives        int // player lives
-	state        GameState
+	mode     mode
+	editor   *editor.Editor
+	playtest *sim.World
+	drag     editorDrag
 
-	physics  *physics.CollisionSystem
-	renderer *render.Renderer
+	pack      *levels.Pack   // pack new runs are played in
+	packs     []*levels.Pack // installed packs shown in the pack list
+	packIndex int            // highlighted entry of the pack list
+
+	dev *devReload // nil unless running in development mode
+
+	scores         *scores.Table
+	scoresPath     string     // empty when the table can't be saved
+	scored         *sim.World // world whose finished run was already offered a high score
+	scoredRun      int        // which of scored's runs that was
+	entryName      []rune     // name being typed on the name-entry screen
+	scoreHighlight int        // entry to highlight on the high-score screen, -1 for none
+
+	audio        *audio.Audio
+	sounds       []audio.Sound     // sounds queued during this frame's ticks
+	settings     settings.Settings // the player's preferences; audio and effects follow them
+	settingsPath string            // empty when settings can't be saved
+	fx           *fx.Emitter
+
+	savePath   string // where a run in progress is saved; empty if saving isn't possible
+	notice     string // short message shown over the game
+	noticeLeft int    // ticks until the notice disappears
+
+	recordPath string
+	recorder   *replay.Recorder
+	player     *replay.Player
 
 	// Track the last enforced window size so we don't loop
 	lastWindowW int
@@ -45,278 +87,248 @@ type Game struct {
 }
 
 // NewGame creates a new game instance
-func NewGame() *Game {
-	// Initialize renderer first since it can fail
-	renderer, err := render.NewRenderer()
-	if err != nil {
-		log.Fatalf("Failed to create renderer: %v", err)
+func NewGame(opts Options) *Game {
+	g := &Game{
+		recordPath:  opts.RecordPath,
+		lastWindowW: 1440,
+		lastWindowH: 1080,
 	}
+	g.loadSettings()
 
-	game := &Game{
-		currentLevel: 1,
-		score:        0,
-		lives:        3,
-		state:        StateStart,
-		physics:      physics.NewCollisionSystem(),
-		renderer:     renderer,
-		lastWindowW:  1440,
-		lastWindowH:  1080,
-	}
-
-	// Initialize game entities
-	game.paddle = entities.NewPaddle()
-
-	// Load the first level
-	if err := game.loadLevel(1); err != nil {
-		log.Printf("Failed to load level 1: %v", err)
-		game.createFallbackLevel()
+	// Initialize renderer first since it can fail
+	var err error
+	if g.renderer, err = newRenderer(opts.Sprites, g.settings.Sprites); err != nil {
+		log.Fatalf("Failed to create renderer: %v", err)
 	}
 
-	// Create ball with level's speed positioned above paddle
-	game.ball = entities.NewBallAbovePaddle(game.paddle.X(), game.level.BallSpeed)
-
-	return game
-}
-
-// loadLevel loads a level from the levels package
-func (g *Game) loadLevel(levelNum int) error {
-	level, err := levels.LoadLevel(levelNum)
-	if err != nil {
-		return err
-	}
+	g.loadScores()
+	g.savePath = defaultSavePath()
+	g.audio = audio.New(g.settings)
+	g.fx = fx.New(g.settings.Effects)
 
-	// Guarantee score baseline: at least 1000 points per level number.
-	baseline := levelNum * 1000
-	if g.score < baseline {
-		g.score = baseline
+	if opts.PackPath != "" {
+		if g.pack, err = levels.OpenPackPath(opts.PackPath); err != nil {
+			log.Fatalf("Failed to open level pack: %v", err)
+		}
 	}
 
-	g.level = level
-	g.bricks = make([]*entities.Brick, len(level.Bricks))
-
-	if level.UsePixelPositioning {
-		// New pixel-perfect format
-		for i, levelBrick := range level.Bricks {
-			g.bricks[i] = entities.NewBrickFromLevelPixel(levelBrick, level.DefaultBrickWidth, level.DefaultBrickHeight)
+	if opts.ReplayPath != "" {
+		rep, err := replay.Load(opts.ReplayPath)
+		if err != nil {
+			log.Fatalf("Failed to load replay: %v", err)
 		}
-	} else {
-		// Legacy grid-based format with row-specific centering
-		// Determine min/max X for every Y row so each row can be centred independently.
-		rowMin := make(map[int]int)
-		rowMax := make(map[int]int)
-		for _, lb := range level.Bricks {
-			if v, ok := rowMin[lb.Y]; !ok || lb.X < v {
-				rowMin[lb.Y] = lb.X
-			}
-			if v, ok := rowMax[lb.Y]; !ok || lb.X > v {
-				rowMax[lb.Y] = lb.X
+		if g.pack == nil {
+			if g.pack, err = findPack(rep.PackID); err != nil {
+				log.Fatalf("Failed to open replay's level pack: %v", err)
 			}
 		}
+		g.world = sim.New(sim.Options{Pack: g.pack, StartLevel: rep.StartLevel, Seed: rep.Seed})
+		for _, problem := range rep.Check(g.world.Level()) {
+			log.Printf("Replay warning: %s; playback may diverge", problem)
+		}
+		g.player = replay.NewPlayer(rep)
+		g.input = g.player
+		return g
+	}
 
-		// Convert level bricks to game entities with row-specific bounds for centring.
-		for i, levelBrick := range level.Bricks {
-			minX := rowMin[levelBrick.Y]
-			maxX := rowMax[levelBrick.Y]
-			g.bricks[i] = entities.NewBrickFromLevelWithBounds(levelBrick,
-				level.BrickWidth, level.BrickHeight, level.BrickSpacingX, level.BrickSpacingY,
-				minX, maxX)
+	if g.pack == nil {
+		if g.pack, err = levels.BuiltinPack(); err != nil {
+			log.Printf("Failed to open builtin levels: %v", err)
 		}
 	}
+	g.world = sim.New(sim.Options{Pack: g.pack, StartLevel: 1, Seed: time.Now().UnixNano()})
+
+	// Keyboard takes priority over the gamepad, which takes priority over the mouse
+	g.input = input.Merge(
+		device.NewKeyboard(),
+		device.NewGamepad(),
+		device.NewMouse(func() float64 { return g.activeWorld().Paddle().X() }),
+	)
+
+	if g.recordPath != "" {
+		header := replay.NewHeader(packID(g.pack), g.world.CurrentLevel(), g.world.Seed(), g.world.Level())
+		g.recorder = replay.NewRecorder(g.input, header)
+		g.input = g.recorder
+	}
 
-	log.Printf("Level loaded: %s with %d bricks (format: %s)", level.Name, len(g.bricks),
-		map[bool]string{true: "pixel-perfect", false: "grid-based"}[level.UsePixelPositioning])
-	return nil
+	if opts.Dev {
+		g.startDev(opts.ConfigDirs)
+	}
+
+	return g
 }
 
-// calculateBrickFieldBounds calculates the minimum and maximum X coordinates used in the level
-func (g *Game) calculateBrickFieldBounds(level *levels.Level) (int, int) {
-	if len(level.Bricks) == 0 {
-		return 0, 0
+// newRenderer creates the renderer with the sprite pack given on the command
+// line, or else the one in the settings. A broken pack from the command line is
+// an error; one from the settings is logged and the default sprites are used.
+func newRenderer(flagPack, settingsPack string) (*render.Renderer, error) {
+	if flagPack != "" || settingsPack == "" {
+		return render.NewRenderer(flagPack)
+	}
+	r, err := render.NewRenderer(settingsPack)
+	if err != nil {
+		log.Printf("Using the default sprites: %v", err)
+		return render.NewRenderer("")
 	}
+	return r, nil
+}
 
-	minX := level.Bricks[0].X
-	maxX := level.Bricks[0].X
+// Close flushes anything that must outlive the window, such as a recording
+func (g *Game) Close() error {
+	g.saveGame()
 
-	for _, brick := range level.Bricks {
-		if brick.X < minX {
-			minX = brick.X
-		}
-		if brick.X > maxX {
-			maxX = brick.X
-		}
+	if g.recorder == nil {
+		return nil
 	}
-
-	return minX, maxX
+	rep := g.recorder.Replay()
+	if err := replay.Save(g.recordPath, rep); err != nil {
+		return err
+	}
+	log.Printf("Saved replay of %d ticks to %s", len(rep.Frames), g.recordPath)
+	return nil
 }
 
-// createFallbackLevel creates a simple level if loading fails
-func (g *Game) createFallbackLevel() {
-	g.level = &levels.Level{
-		Name:          "Default Level",
-		BrickWidth:    150,
-		BrickHeight:   60,
-		BrickSpacingX: 40,
-		BrickSpacingY: 30,
-		BallSpeed:     400,
-	}
+// SetInputSource replaces the devices driving the game, e.g. with a bot
+func (g *Game) SetInputSource(src input.InputSource) {
+	g.input = src
+}
 
-	// Create a simple pattern of bricks with fallback sizing
-	g.bricks = []*entities.Brick{
-		entities.NewBrickFromLevelWithBounds(entities.LevelBrick{X: 2, Y: 2, BrickType: "standard", Hits: 1}, 150, 60, 40, 30, 2, 5),
-		entities.NewBrickFromLevelWithBounds(entities.LevelBrick{X: 3, Y: 2, BrickType: "standard", Hits: 1}, 150, 60, 40, 30, 2, 5),
-		entities.NewBrickFromLevelWithBounds(entities.LevelBrick{X: 4, Y: 2, BrickType: "standard", Hits: 1}, 150, 60, 40, 30, 2, 5),
-		entities.NewBrickFromLevelWithBounds(entities.LevelBrick{X: 5, Y: 2, BrickType: "standard", Hits: 1}, 150, 60, 40, 30, 2, 5),
+// activeWorld returns the world currently being played
+func (g *Game) activeWorld() *sim.World {
+	if g.mode == modePlaytest {
+		return g.playtest
 	}
+	return g.world
 }
 
 // Update implements ebiten.Game interface
 func (g *Game) Update() error {
-	switch g.state {
-	case StateStart:
-		return g.updateStart()
-	case StatePlaying:
-		return g.updatePlaying()
-	case StatePaused:
-		return g.updatePaused()
-	case StateLevelComplete:
-		return g.updateLevelComplete()
-	case StateWaitingToContinue:
-		return g.updateWaitingToContinue()
-	case StateGameOver:
-		return g.updateGameOver()
+	g.updateDev()
+	if g.noticeLeft > 0 {
+		g.noticeLeft--
 	}
-	return nil
-}
+	defer g.updateAudio()
 
-// updateStart handles start screen input
-func (g *Game) updateStart() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyRight) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyD) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		g.state = StatePlaying
+	switch g.mode {
+	case modeEdit:
+		g.updateEditor()
+		return nil
+	case modePlaytest:
+		g.updatePlaytest()
+		return nil
+	case modePacks:
+		g.updatePackList()
+		return nil
+	case modeNameEntry:
+		g.updateNameEntry()
+		return nil
+	case modeScores:
+		g.updateScores()
+		return nil
 	}
-	return nil
-}
 
-// updatePlaying handles main game logic
-func (g *Game) updatePlaying() error {
-	// Check for pause input using IsKeyJustPressed to prevent flickering
-	if inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-		g.state = StatePaused
+	if g.player == nil && g.world.State() == sim.StateStart && inpututil.IsKeyJustPressed(ebiten.KeyH) {
+		g.openScores(-1)
 		return nil
 	}
-
-	// Update paddle
-	g.paddle.Update()
-
-	// Update ball
-	g.ball.Update()
-
-	// Check collisions
-	g.physics.CheckPaddleCollision(g.ball, g.paddle, &g.score, g.lives)
-	g.physics.CheckBrickCollisions(g.ball, g.bricks, &g.score, g.lives)
-	g.physics.CheckWallCollisions(g.ball)
-
-	// Check if ball is lost
-	if g.ball.IsLost() {
-		g.lives-- // Subtract life immediately when ball is lost
-		if g.lives <= 0 {
-			g.state = StateGameOver
-		} else {
-			g.state = StateWaitingToContinue
-		}
+	if g.world.State() == sim.StateStart && inpututil.IsKeyJustPressed(ebiten.KeyR) && g.canResume() {
+		g.resumeGame()
+		return nil
 	}
+	g.updateSaveKeys()
 
-	// Check if level is complete
-	activeBricks := 0
-	for _, brick := range g.bricks {
-		if brick.IsActive() {
-			activeBricks++
+	// The editor and pack list are reachable from the start screen unless a replay is being recorded or played
+	if g.recorder == nil && g.player == nil && g.world.State() == sim.StateStart {
+		if inpututil.IsKeyJustPressed(ebiten.KeyE) && g.world.Pack() != nil {
+			g.openEditor()
+			return nil
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyL) {
+			g.openPackList()
+			return nil
 		}
 	}
 
-	if activeBricks == 0 {
-		// Level complete - could advance to next level here
-		g.state = StateLevelComplete
+	ticks := 1
+	if g.player != nil {
+		g.updatePlaybackControls()
+		ticks = g.player.TicksThisFrame()
 	}
 
-	return nil
-}
-
-// updateWaitingToContinue handles waiting to continue after losing a life
-func (g *Game) updateWaitingToContinue() error {
-	// Check for any input to continue
-	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyRight) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyD) ||
-		inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-
-		// Reset ball position and continue playing (life already decremented)
-		g.ball = entities.NewBallAbovePaddle(g.paddle.X(), g.level.BallSpeed)
-		g.state = StatePlaying
+	g.updateVolumeKeys()
+	g.updateEffectsKey()
+	before := g.world.State()
+	for i := 0; i < ticks; i++ {
+		g.world.Step(g.input.Poll())
+		g.handleTick(g.world)
 	}
+	g.dropStaleSave(before)
+	g.checkHighScore()
 	return nil
 }
 
-// updateGameOver handles game over state
-func (g *Game) updateGameOver() error {
-	// Could handle restart logic here
-	return nil
-}
+// Draw implements ebiten.Game interface
+func (g *Game) Draw(screen *ebiten.Image) {
+	switch g.mode {
+	case modeEdit:
+		g.renderer.SetTheme(themes.Get(g.editor.Level().Theme))
+		g.renderer.DrawEditor(screen, g.editor)
+	case modePlaytest:
+		g.drawWorld(screen, g.playtest)
+		g.renderer.DrawPlaytestOverlay(screen)
+	case modePacks:
+		g.renderer.DrawPackList(screen, g.packs, g.packIndex)
+	case modeNameEntry:
+		g.renderer.DrawNameEntry(screen, g.world.Score(), string(g.entryName))
+	case modeScores:
+		g.renderer.DrawHighScores(screen, packTitle(g.world.Pack()), g.scores.Top(packID(g.world.Pack())), g.scoreHighlight)
+	default:
+		g.drawWorld(screen, g.world)
+		if g.player != nil {
+			g.renderer.DrawReplayOverlay(screen, g.player.Frame(), g.player.Len(), g.player.Speed(), g.player.Paused())
+		}
+	}
 
-// updatePaused handles pause screen input
-func (g *Game) updatePaused() error {
-	// Check for any input to resume using IsKeyJustPressed to prevent flickering
-	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyRight) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyD) ||
-		inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		g.state = StatePlaying
+	if g.noticeLeft > 0 {
+		g.renderer.DrawNotice(screen, g.notice)
+	}
+	if lines := g.dev.errorLines(); len(lines) > 0 {
+		g.renderer.DrawErrorOverlay(screen, lines)
 	}
-	return nil
 }
 
-// updateLevelComplete handles level complete state
-func (g *Game) updateLevelComplete() error {
-	// Check for any input to advance to next level
-	if inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyRight) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyD) ||
-		inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-
-		// Try to advance to the next level
-		nextLevel := g.currentLevel + 1
-		if err := g.loadLevel(nextLevel); err != nil {
-			// No more levels - game complete!
-			log.Printf("No level %d found, game complete!", nextLevel)
-			g.state = StateGameOver
-		} else {
-			// Successfully loaded next level
-			g.currentLevel = nextLevel
-			g.ball = entities.NewBallAbovePaddle(g.paddle.X(), g.level.BallSpeed)
-			g.state = StatePlaying
-			log.Printf("Advanced to level %d", nextLevel)
-		}
+// drawWorld draws the screen for the world's current state
+func (g *Game) drawWorld(screen *ebiten.Image, w *sim.World) {
+	g.renderer.SetTheme(themes.Get(w.Level().Theme))
+	switch w.State() {
+	case sim.StateStart:
+		g.renderer.DrawStartScreen(screen, w.Level().Name, packTitle(w.Pack()), w == g.world && g.canResume())
+	case sim.StatePlaying:
+		g.renderer.DrawGame(screen, w.Paddle(), w.Balls(), w.Bricks(), w.Capsules(), w.Level().Name, w.CurrentLevel(), w.Score(), w.Lives())
+		g.renderer.DrawEffects(screen, g.fx)
+	case sim.StatePaused:
+		g.renderer.DrawPauseScreen(screen, w == g.world && g.savePath != "" && g.player == nil)
+	case sim.StateLevelComplete:
+		g.renderer.DrawLevelComplete(screen)
+	case sim.StateWaitingToContinue:
+		g.renderer.DrawWaitingToContinue(screen, w.Lives())
+	case sim.StateGameOver:
+		g.renderer.DrawGameOver(screen, w.Score(), g.endLabels(w), w.EndChoice())
+	case sim.StateCampaignComplete:
+		g.renderer.DrawCampaignComplete(screen, w.Score(), g.endLabels(w), w.EndChoice())
 	}
-	return nil
 }
 
-// Draw implements ebiten.Game interface
-func (g *Game) Draw(screen *ebiten.Image) {
-	switch g.state {
-	case StateStart:
-		g.renderer.DrawStartScreen(screen, g.level.Name)
-	case StatePlaying:
-		g.renderer.DrawGame(screen, g.paddle, g.ball, g.bricks, g.level.Name, g.currentLevel, g.score, g.lives)
-	case StatePaused:
-		g.renderer.DrawPauseScreen(screen)
-	case StateLevelComplete:
-		g.renderer.DrawLevelComplete(screen)
-	case StateWaitingToContinue:
-		g.renderer.DrawWaitingToContinue(screen, g.lives)
-	case StateGameOver:
-		g.renderer.DrawGameOver(screen, g.score)
+// endLabels returns the end-of-run menu for w; playtests have none since any key returns to the editor
+func (g *Game) endLabels(w *sim.World) []string {
+	if w == g.playtest {
+		return nil
+	}
+	var labels []string
+	for _, o := range w.EndOptions() {
+		labels = append(labels, o.String())
 	}
+	return labels
 }
 
 // Layout implements ebiten.Game interface
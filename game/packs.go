@@ -0,0 +1,82 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/levels"
+	"BRIX/sim"
+)
+
+// packID returns the identifier recorded for a pack, tolerating a missing pack
+func packID(p *levels.Pack) string {
+	if p == nil {
+		return ""
+	}
+	return p.ID()
+}
+
+// packTitle returns a pack's display title, tolerating a missing pack
+func packTitle(p *levels.Pack) string {
+	if p == nil {
+		return ""
+	}
+	return p.Title
+}
+
+// findPack locates an installed pack by its identifier
+func findPack(id string) (*levels.Pack, error) {
+	if id == "" || id == levels.BuiltinPackID {
+		return levels.BuiltinPack()
+	}
+	packs, _ := levels.DiscoverPacks(levels.PackDirs()...)
+	for _, p := range packs {
+		if p.ID() == id {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("level pack %q is not installed", id)
+}
+
+// openPackList rescans the installed packs and shows the pack list
+func (g *Game) openPackList() {
+	packs, errs := levels.DiscoverPacks(levels.PackDirs()...)
+	for _, err := range errs {
+		log.Printf("Skipping level pack: %v", err)
+	}
+	if len(packs) == 0 {
+		return
+	}
+
+	g.packs = packs
+	g.packIndex = 0
+	for i, p := range packs {
+		if g.pack != nil && p.Source() == g.pack.Source() {
+			g.packIndex = i
+		}
+	}
+	g.mode = modePacks
+}
+
+// updatePackList moves the highlight and starts the chosen pack
+func (g *Game) updatePackList() {
+	pressed := inpututil.IsKeyJustPressed
+
+	switch {
+	case pressed(ebiten.KeyUp) || pressed(ebiten.KeyW):
+		g.packIndex = (g.packIndex + len(g.packs) - 1) % len(g.packs)
+	case pressed(ebiten.KeyDown) || pressed(ebiten.KeyS):
+		g.packIndex = (g.packIndex + 1) % len(g.packs)
+	case pressed(ebiten.KeyEnter) || pressed(ebiten.KeySpace):
+		g.pack = g.packs[g.packIndex]
+		g.world = sim.New(sim.Options{Pack: g.pack, StartLevel: 1, Seed: time.Now().UnixNano()})
+		g.mode = modePlay
+		log.Printf("Playing level pack %q (%d levels)", g.pack.Title, g.pack.Len())
+	case pressed(ebiten.KeyEscape):
+		g.mode = modePlay
+	}
+}
@@ -0,0 +1,19 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// updatePlaybackControls handles fast-forward, pause and frame-step keys during replay playback
+func (g *Game) updatePlaybackControls() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		g.player.CycleSpeed()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		g.player.TogglePause()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyPeriod) {
+		g.player.StepFrame()
+	}
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/sim"
+)
+
+// noticeTicks is how long a notice such as "Game saved" stays on screen (2 seconds)
+const noticeTicks = 120
+
+// defaultSavePath returns where a game in progress is saved, or "" if there is no user config directory
+func defaultSavePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "BRIX", "save.json")
+}
+
+// canResume reports whether a saved game can be resumed from the start screen
+func (g *Game) canResume() bool {
+	return g.savePath != "" && g.recorder == nil && g.player == nil && sim.SnapshotExists(g.savePath)
+}
+
+// saveGame writes the run in progress to disk, if there is one
+func (g *Game) saveGame() bool {
+	if g.savePath == "" || g.player != nil || !g.world.CanSave() {
+		return false
+	}
+	if err := sim.SaveSnapshot(g.savePath, g.world.Snapshot()); err != nil {
+		log.Printf("Failed to save game: %v", err)
+		g.showNotice("Saving failed")
+		return false
+	}
+	log.Printf("Saved game at level %d to %s", g.world.CurrentLevel(), g.savePath)
+	return true
+}
+
+// resumeGame replaces the world with the saved run and removes the save so it is only resumed once
+func (g *Game) resumeGame() {
+	snap, err := sim.LoadSnapshot(g.savePath)
+	if err != nil {
+		log.Printf("Failed to load saved game: %v", err)
+		g.showNotice("Saved game could not be loaded")
+		return
+	}
+
+	pack := g.pack
+	if packID(pack) != snap.PackID {
+		if pack, err = findPack(snap.PackID); err != nil {
+			log.Printf("Failed to resume saved game: %v", err)
+			g.showNotice("Saved game's level pack is missing")
+			return
+		}
+	}
+	w, err := sim.Restore(snap, pack)
+	if err != nil {
+		log.Printf("Failed to resume saved game: %v", err)
+		g.showNotice("Saved game no longer matches its level")
+		return
+	}
+
+	g.world, g.pack = w, pack
+	if err := os.Remove(g.savePath); err != nil {
+		log.Printf("Failed to remove resumed save: %v", err)
+	}
+	log.Printf("Resumed saved game at level %d", w.CurrentLevel())
+}
+
+// dropStaleSave removes the saved game once the run has ended or a new one has
+// started from the start screen, so an old save can't be resumed over it
+func (g *Game) dropStaleSave(before sim.GameState) {
+	if g.savePath == "" || g.player != nil {
+		return
+	}
+	now := g.world.State()
+	if now == before || !(g.world.Finished() || before == sim.StateStart && now == sim.StatePlaying) {
+		return
+	}
+	if err := os.Remove(g.savePath); err == nil {
+		log.Printf("Removed saved game %s", g.savePath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Failed to remove saved game: %v", err)
+	}
+}
+
+// updateSaveKeys handles saving from the pause screen
+func (g *Game) updateSaveKeys() {
+	if g.world.State() == sim.StatePaused && inpututil.IsKeyJustPressed(ebiten.KeyS) && g.saveGame() {
+		g.showNotice("Game saved")
+	}
+}
+
+// showNotice displays a short message over the game
+func (g *Game) showNotice(text string) {
+	g.notice, g.noticeLeft = text, noticeTicks
+}
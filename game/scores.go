@@ -0,0 +1,91 @@
+package game
+
+import (
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/scores"
+)
+
+// loadScores opens the high-score table. If the file can't be read the table
+// starts empty and is never saved, so a damaged or newer file isn't overwritten.
+func (g *Game) loadScores() {
+	path, err := scores.DefaultPath()
+	if err == nil {
+		g.scores, err = scores.Load(path)
+	}
+	if err != nil {
+		log.Printf("High scores will not be saved: %v", err)
+		g.scores = scores.New()
+		return
+	}
+	g.scoresPath = path
+}
+
+// checkHighScore offers name entry once per finished run that made the table
+func (g *Game) checkHighScore() {
+	w := g.world
+	if g.player != nil || !w.Finished() || (g.scored == w && g.scoredRun == w.Run()) {
+		return
+	}
+	g.scored, g.scoredRun = w, w.Run()
+	if !g.scores.Qualifies(packID(w.Pack()), w.Score()) {
+		return
+	}
+	g.entryName = nil
+	g.mode = modeNameEntry
+}
+
+// updateNameEntry collects the player's name and records the score on Enter
+func (g *Game) updateNameEntry() {
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if unicode.IsPrint(r) && len(g.entryName) < scores.MaxNameLength {
+			g.entryName = append(g.entryName, r)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(g.entryName) > 0 {
+		g.entryName = g.entryName[:len(g.entryName)-1]
+	}
+	if !inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		return
+	}
+
+	name := strings.TrimSpace(string(g.entryName))
+	if name == "" {
+		name = "PLAYER"
+	}
+	w := g.world
+	pack := packID(w.Pack())
+	rank := g.scores.Add(pack, scores.Entry{
+		Name:  name,
+		Score: w.Score(),
+		Level: w.CurrentLevel(),
+		Lives: w.Lives(),
+		Date:  time.Now(),
+	})
+	if g.scoresPath != "" {
+		if err := scores.Save(g.scoresPath, g.scores); err != nil {
+			log.Printf("Failed to save high scores: %v", err)
+		}
+	}
+	g.openScores(rank)
+}
+
+// openScores shows the current pack's high-score table with one entry highlighted (-1 for none)
+func (g *Game) openScores(highlight int) {
+	g.scoreHighlight = highlight
+	g.mode = modeScores
+}
+
+// updateScores returns from the high-score table
+func (g *Game) updateScores() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) ||
+		inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.mode = modePlay
+	}
+}
@@ -0,0 +1,61 @@
+package device
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/input"
+)
+
+// stickDeadZone ignores small analogue stick drift
+const stickDeadZone = 0.2
+
+// Gamepad maps every connected standard-layout gamepad to actions: left stick or
+// D-pad to move, A to launch/confirm, Start to pause and B to go back.
+type Gamepad struct {
+	ids []ebiten.GamepadID
+}
+
+// NewGamepad creates a gamepad input source
+func NewGamepad() *Gamepad {
+	return &Gamepad{}
+}
+
+// Poll implements input.InputSource
+func (g *Gamepad) Poll() input.Actions {
+	var a input.Actions
+
+	g.ids = ebiten.AppendGamepadIDs(g.ids[:0])
+	for _, id := range g.ids {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+
+		if a.Move == 0 {
+			stick := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+			if math.Abs(stick) > stickDeadZone {
+				a.Move = stick
+			}
+			if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftLeft) {
+				a.Move = -1
+			}
+			if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftRight) {
+				a.Move = 1
+			}
+		}
+
+		confirm := inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonRightBottom)
+		start := inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonCenterRight)
+
+		a.Launch = a.Launch || confirm ||
+			inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonLeftLeft) ||
+			inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonLeftRight)
+		a.Pause = a.Pause || start
+		a.Confirm = a.Confirm || confirm || start
+		a.Back = a.Back || inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonRightRight)
+	}
+
+	return a
+}
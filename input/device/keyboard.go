@@ -0,0 +1,40 @@
+// Package device implements input.InputSource for Ebitengine devices.
+package device
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/input"
+)
+
+// Keyboard maps Left/Right/A/D, Space/Enter and Escape to actions
+type Keyboard struct{}
+
+// NewKeyboard creates a keyboard input source
+func NewKeyboard() *Keyboard {
+	return &Keyboard{}
+}
+
+// Poll implements input.InputSource
+func (k *Keyboard) Poll() input.Actions {
+	var a input.Actions
+
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
+		a.Move = -1
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
+		a.Move = 1
+	}
+
+	// Starting to move launches play from the start screen
+	a.Launch = inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyRight) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyD)
+
+	// Use IsKeyJustPressed to prevent pause flickering
+	a.Pause = inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter)
+	a.Confirm = a.Pause
+	a.Back = inpututil.IsKeyJustPressed(ebiten.KeyEscape)
+
+	return a
+}
@@ -0,0 +1,62 @@
+package device
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"BRIX/entities"
+	"BRIX/input"
+)
+
+// mouseDeadZone is how close (px) the paddle must get to the cursor before the
+// mouse stops steering.
+const mouseDeadZone = 8.0
+
+// Mouse maps left clicks to launch/confirm and steers the paddle towards the
+// cursor. Steering only kicks in once the cursor moves and stops again when the
+// paddle reaches it, so an idle mouse never fights the keyboard.
+type Mouse struct {
+	paddleX func() float64 // current paddle centre; nil disables steering
+
+	lastX    int
+	steering bool
+}
+
+// NewMouse creates a mouse input source. paddleX reports the paddle's centre
+// and may be nil to use the mouse for clicks only.
+func NewMouse(paddleX func() float64) *Mouse {
+	x, _ := ebiten.CursorPosition()
+	return &Mouse{paddleX: paddleX, lastX: x}
+}
+
+// Poll implements input.InputSource
+func (m *Mouse) Poll() input.Actions {
+	var a input.Actions
+
+	click := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	a.Launch = click
+	a.Confirm = click
+
+	if m.paddleX == nil {
+		return a
+	}
+
+	x, _ := ebiten.CursorPosition()
+	if x != m.lastX {
+		m.lastX = x
+		m.steering = true
+	}
+	if !m.steering {
+		return a
+	}
+
+	dx := float64(x) - m.paddleX()
+	if math.Abs(dx) <= mouseDeadZone {
+		m.steering = false
+		return a
+	}
+	a.Move = math.Max(-1, math.Min(1, dx/(entities.PaddleWidth/2)))
+	return a
+}
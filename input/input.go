@@ -0,0 +1,94 @@
+// Package input turns raw devices into abstract player actions. Game logic only
+// ever sees Actions, so new devices and bots plug in by implementing InputSource.
+// Ebitengine-backed devices live in the input/device subpackage so this package
+// stays usable in headless builds.
+package input
+
+// Actions is the abstract player intent for a single tick
+type Actions struct {
+	Move    float64 // paddle axis, -1 (full left) .. 1 (full right)
+	Launch  bool    // start playing / launch the ball
+	Pause   bool    // pause while playing
+	Confirm bool    // acknowledge the current screen (resume, continue, next level)
+	Back    bool    // leave the current screen
+}
+
+// Any reports whether any of the launch, pause or confirm buttons was pressed.
+func (a Actions) Any() bool {
+	return a.Launch || a.Pause || a.Confirm
+}
+
+// InputSource produces one set of Actions per tick
+type InputSource interface {
+	Poll() Actions
+}
+
+// SourceFunc adapts an ordinary function (e.g. a bot) to the InputSource interface
+type SourceFunc func() Actions
+
+// Poll calls f
+func (f SourceFunc) Poll() Actions {
+	return f()
+}
+
+// Merged combines several sources: buttons are OR-ed together and the first
+// source with a non-zero move axis wins, so earlier sources take priority.
+type Merged []InputSource
+
+// Merge returns a source combining all given sources in priority order
+func Merge(sources ...InputSource) Merged {
+	return Merged(sources)
+}
+
+// Poll polls every source exactly once and combines the results
+func (m Merged) Poll() Actions {
+	var out Actions
+	for _, src := range m {
+		a := src.Poll()
+		if out.Move == 0 {
+			out.Move = a.Move
+		}
+		out.Launch = out.Launch || a.Launch
+		out.Pause = out.Pause || a.Pause
+		out.Confirm = out.Confirm || a.Confirm
+		out.Back = out.Back || a.Back
+	}
+	return out
+}
+
+// Scripted replays a fixed sequence of Actions, one per tick. Once the script is
+// exhausted it keeps returning the zero Actions.
+type Scripted struct {
+	frames []Actions
+	pos    int
+}
+
+// NewScripted creates a scripted source from per-tick actions
+func NewScripted(frames ...Actions) *Scripted {
+	return &Scripted{frames: frames}
+}
+
+// Poll returns the next scripted frame
+func (s *Scripted) Poll() Actions {
+	if s.pos >= len(s.frames) {
+		return Actions{}
+	}
+	a := s.frames[s.pos]
+	s.pos++
+	return a
+}
+
+// Done reports whether every scripted frame has been consumed
+func (s *Scripted) Done() bool {
+	return s.pos >= len(s.frames)
+}
+
+// Pos returns the index of the next frame to be returned
+func (s *Scripted) Pos() int {
+	return s.pos
+}
+
+// Len returns the total number of scripted frames
+func (s *Scripted) Len() int {
+	return len(s.frames)
+}
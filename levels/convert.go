@@ -0,0 +1,124 @@
+package levels
+
+import (
+	"fmt"
+	"math"
+
+	"BRIX/entities"
+)
+
+// Grid spacing used when converting a pixel level to the grid format
+const (
+	DefaultSpacingX = 20
+	DefaultSpacingY = 20
+)
+
+// ToPixel migrates a grid level to the pixel format without changing how it
+// looks: the legacy path's auto-fit shrinking and per-row centring are baked
+// into each brick's position, so every brick keeps the exact rectangle it had
+// on screen (see VerifyMigration). Pixel levels are returned as a copy.
+func ToPixel(level *Level) *Level {
+	out := level.Clone()
+	if out.UsePixelPositioning {
+		return out
+	}
+	AutoFitLevel(out)
+
+	bricks := BuildBricks(out)
+	converted := make([]entities.LevelBrick, len(out.Bricks))
+	for i, lb := range out.Bricks {
+		left, top, _, _ := bricks[i].GetBounds()
+		converted[i] = entities.LevelBrick{
+			PixelX: left - entities.GameAreaLeft,
+			PixelY: top - entities.GameAreaTop,
+			Type:   lb.TypeName(),
+			Hits:   lb.Hits,
+		}
+	}
+
+	out.Bricks = converted
+	out.UsePixelPositioning = true
+	out.DefaultBrickWidth, out.DefaultBrickHeight = out.BrickWidth, out.BrickHeight
+	out.BrickWidth, out.BrickHeight = 0, 0
+	out.BrickSpacingX, out.BrickSpacingY = 0, 0
+	return out
+}
+
+// VerifyMigration checks that a migrated level builds bricks with exactly the
+// same on-screen bounds, types and hits as the original, returning a Problem
+// for every brick that differs.
+func VerifyMigration(original, migrated *Level) error {
+	prepared := original.Clone()
+	if !prepared.UsePixelPositioning {
+		AutoFitLevel(prepared)
+	}
+	want, got := BuildBricks(prepared), BuildBricks(migrated)
+	if len(want) != len(got) {
+		return Problems{{Message: fmt.Sprintf("migration produced %d bricks, want %d", len(got), len(want))}}
+	}
+
+	var problems Problems
+	for i := range want {
+		wl, wt, wr, wb := want[i].GetBounds()
+		gl, gt, gr, gb := got[i].GetBounds()
+		if wl != gl || wt != gt || wr != gr || wb != gb {
+			problems = append(problems, Problem{Message: fmt.Sprintf(
+				"brick %d moved from (%g, %g)-(%g, %g) to (%g, %g)-(%g, %g)", i, wl, wt, wr, wb, gl, gt, gr, gb)})
+		}
+		if want[i].Type() != got[i].Type() || want[i].Hits() != got[i].Hits() {
+			problems = append(problems, Problem{Message: fmt.Sprintf(
+				"brick %d changed from %s/%d hits to %s/%d hits", i, want[i].Type(), want[i].Hits(), got[i].Type(), got[i].Hits())})
+		}
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// ToGrid returns a grid-format copy of a pixel level, snapping each brick to
+// the nearest cell of a grid with the level's default brick size and the given
+// spacing. Bricks landing on an occupied cell are dropped; the count is returned.
+// Grid levels are returned as a copy.
+func ToGrid(level *Level, spacingX, spacingY int) (*Level, int) {
+	out := level.Clone()
+	if !out.UsePixelPositioning {
+		return out, 0
+	}
+
+	w, h := out.DefaultBrickWidth, out.DefaultBrickHeight
+	strideX, strideY := float64(w+spacingX), float64(h+spacingY)
+
+	bricks := BuildBricks(out)
+	occupied := make(map[[2]int]bool)
+	var converted []entities.LevelBrick
+	dropped := 0
+	for i, lb := range out.Bricks {
+		left, top, _, _ := bricks[i].GetBounds()
+		x := clamp(int(math.Round((left-entities.GameAreaLeft)/strideX)), 0, entities.BrickCols-1)
+		y := clamp(int(math.Round((top-entities.GameAreaTop)/strideY)), 0, entities.BrickRows-1)
+		if occupied[[2]int{x, y}] {
+			dropped++
+			continue
+		}
+		occupied[[2]int{x, y}] = true
+		converted = append(converted, entities.LevelBrick{X: x, Y: y, BrickType: lb.TypeName(), Hits: lb.Hits})
+	}
+
+	out.Bricks = converted
+	out.UsePixelPositioning = false
+	out.BrickWidth, out.BrickHeight = w, h
+	out.BrickSpacingX, out.BrickSpacingY = spacingX, spacingY
+	out.DefaultBrickWidth, out.DefaultBrickHeight = 0, 0
+	return out, dropped
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,36 @@
+package levels
+
+import "testing"
+
+// TestToPixelRoundTrip migrates every builtin level to the pixel format, writes
+// and rereads it as the convert command does, and checks every brick keeps the
+// exact bounds, type and hits it had in the grid format.
+func TestToPixelRoundTrip(t *testing.T) {
+	pack, err := BuiltinPack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 1; n <= pack.Len(); n++ {
+		name := pack.LevelName(n)
+		t.Run(name, func(t *testing.T) {
+			level, err := pack.ReadLevel(n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := EncodeLevel(ToPixel(level))
+			if err != nil {
+				t.Fatal(err)
+			}
+			reread, err := ParseLevel(encoded, name)
+			if err != nil {
+				t.Fatalf("converted level doesn't parse: %v", err)
+			}
+			if !reread.UsePixelPositioning {
+				t.Fatal("converted level isn't in the pixel format")
+			}
+			if err := VerifyMigration(level, reread); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package levels
+
+import "embed"
+
+// embeddedLevels holds the builtin pack so the game runs from any directory
+//
+//go:embed pack.json level*.json
+var embeddedLevels embed.FS
@@ -1,12 +1,17 @@
 package levels
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"fmt"
-	"os"
-	"path/filepath"
+	"math"
+	"path"
+	"slices"
+	"strings"
 
+	"BRIX/config"
 	"BRIX/entities"
+	"BRIX/themes"
 )
 
 // Level represents a complete level configuration
@@ -24,26 +29,59 @@ type Level struct {
 	DefaultBrickWidth   int  `json:"default_brick_width,omitempty"`   // default width if not specified per brick
 	DefaultBrickHeight  int  `json:"default_brick_height,omitempty"`  // default height if not specified per brick
 
-	BallSpeed float64               `json:"ball_speed"` // ball speed in pixels per second
-	Bricks    []entities.LevelBrick `json:"bricks"`
+	BallSpeed    float64 `json:"ball_speed"`               // per-axis launch speed in pixels per second
+	MaxBallSpeed float64 `json:"max_ball_speed,omitempty"` // cap on the ball's actual speed (px/s)
+	MinBallSpeed float64 `json:"min_ball_speed,omitempty"` // floor on the ball's actual speed (px/s)
+
+	Theme string `json:"theme,omitempty"` // look of the level, from the themes registry; the default theme if empty
+	Music string `json:"music,omitempty"` // background music file in the level's pack (.ogg, .mp3 or .wav), looped
+
+	Bricks []entities.LevelBrick `json:"bricks"`
+
+	src *source // where the level was parsed from, for locating problems
 }
 
-// LoadLevel loads a level from a JSON file
-func LoadLevel(levelNum int) (*Level, error) {
-	filename := filepath.Join("levels", fmt.Sprintf("level%d.json", levelNum))
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read level file %s: %v", filename, err)
+// breakable reports whether a level brick's type can be destroyed
+func breakable(lb entities.LevelBrick) bool {
+	return !entities.ParseBrickType(lb.TypeName()).Config().Has(config.BehaviourUnbreakable)
+}
+
+// MusicFormats are the file extensions a level's music may have
+var MusicFormats = []string{".ogg", ".mp3", ".wav"}
+
+// Default speed limits relative to the ball's launch speed when a level doesn't set them
+const (
+	DefaultMaxSpeedRatio = 2.0
+	DefaultMinSpeedRatio = 0.5
+)
+
+// SpeedLimits returns the level's minimum and maximum ball speed in px/s,
+// falling back to multiples of the launch speed for limits left unset.
+func (l *Level) SpeedLimits() (minSpeed, maxSpeed float64) {
+	launch := math.Hypot(l.BallSpeed, l.BallSpeed) // the ball launches diagonally
+	minSpeed, maxSpeed = l.MinBallSpeed, l.MaxBallSpeed
+	if minSpeed == 0 {
+		minSpeed = launch * DefaultMinSpeedRatio
 	}
+	if maxSpeed == 0 {
+		maxSpeed = launch * DefaultMaxSpeedRatio
+	}
+	return minSpeed, maxSpeed
+}
 
-	var level Level
-	err = json.Unmarshal(data, &level)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse level file %s: %v", filename, err)
+// ParseLevel strictly decodes a level file and detects its format, but neither
+// auto-fits nor validates a level that decodes cleanly. name is used in error
+// messages. Unknown fields and malformed values are reported as Problems with
+// their line numbers, together with whatever validation finds in the rest of
+// the level, so one run shows everything wrong with the file.
+func ParseLevel(data []byte, name string) (*Level, error) {
+	level, problems := decodeLevel(data, name)
+	if level == nil {
+		return nil, problems
 	}
 
 	// Auto-detect pixel positioning format
-	if level.UsePixelPositioning || isPixelFormat(&level) {
+	if level.UsePixelPositioning || isPixelFormat(level) {
 		level.UsePixelPositioning = true
 		// Set reasonable defaults for pixel format
 		if level.DefaultBrickWidth == 0 {
@@ -52,17 +90,105 @@ func LoadLevel(levelNum int) (*Level, error) {
 		if level.DefaultBrickHeight == 0 {
 			level.DefaultBrickHeight = 60 // default brick height
 		}
-	} else {
+	}
+
+	if len(problems) > 0 {
+		problems = append(problems, AsProblems(PrepareLevel(level, name))...)
+		slices.SortStableFunc(problems, func(a, b Problem) int { return a.Line - b.Line })
+		return nil, problems
+	}
+	return level, nil
+}
+
+// PrepareLevel readies a parsed level for play: grid levels are auto-fitted and
+// the result is validated. name is used in error messages.
+func PrepareLevel(level *Level, name string) error {
+	if !level.UsePixelPositioning {
 		// Legacy grid-based format - apply auto-fit if needed
-		AutoFitLevel(&level)
+		AutoFitLevel(level)
 	}
 
 	// Validate the level
-	if err := ValidateLevel(&level); err != nil {
-		return nil, fmt.Errorf("level validation failed for %s: %v", filename, err)
+	if err := ValidateLevel(level); err != nil {
+		problems := AsProblems(err)
+		for i := range problems {
+			if problems[i].File == "" {
+				problems[i].File = name
+			}
+		}
+		return problems
+	}
+	return nil
+}
+
+// EncodeLevel returns a level as indented JSON in the layout of the shipped level files
+func EncodeLevel(level *Level) ([]byte, error) {
+	data, err := json.MarshalIndent(level, "", "  ")
+	if err != nil {
+		return nil, err
 	}
+	return append(data, '\n'), nil
+}
+
+// Clone returns a deep copy of the level. The copy forgets the level's file
+// positions since its bricks are expected to change.
+func (l *Level) Clone() *Level {
+	c := *l
+	c.src = nil
+	c.Bricks = append([]entities.LevelBrick(nil), l.Bricks...)
+	return &c
+}
+
+// BuildBricks creates the game bricks for a level exactly as they appear on screen
+func BuildBricks(level *Level) []*entities.Brick {
+	bricks := make([]*entities.Brick, len(level.Bricks))
+
+	if level.UsePixelPositioning {
+		// New pixel-perfect format
+		for i, levelBrick := range level.Bricks {
+			bricks[i] = entities.NewBrickFromLevelPixel(levelBrick, level.DefaultBrickWidth, level.DefaultBrickHeight)
+		}
+		return bricks
+	}
+
+	// Legacy grid-based format with row-specific centering
+	// Determine min/max X for every Y row so each row can be centred independently.
+	rowMin, rowMax := RowBounds(level.Bricks)
 
-	return &level, nil
+	// Convert level bricks to game entities with row-specific bounds for centring.
+	for i, levelBrick := range level.Bricks {
+		minX := rowMin[levelBrick.Y]
+		maxX := rowMax[levelBrick.Y]
+		bricks[i] = entities.NewBrickFromLevelWithBounds(levelBrick,
+			level.BrickWidth, level.BrickHeight, level.BrickSpacingX, level.BrickSpacingY,
+			minX, maxX)
+	}
+	return bricks
+}
+
+// RowBounds returns the minimum and maximum grid X of every grid row
+func RowBounds(bricks []entities.LevelBrick) (rowMin, rowMax map[int]int) {
+	rowMin = make(map[int]int)
+	rowMax = make(map[int]int)
+	for _, lb := range bricks {
+		if v, ok := rowMin[lb.Y]; !ok || lb.X < v {
+			rowMin[lb.Y] = lb.X
+		}
+		if v, ok := rowMax[lb.Y]; !ok || lb.X > v {
+			rowMax[lb.Y] = lb.X
+		}
+	}
+	return rowMin, rowMax
+}
+
+// Hash returns a stable digest of the level's decoded contents.
+func Hash(level *Level) string {
+	raw, err := json.Marshal(level)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
 }
 
 // isPixelFormat auto-detects if this is a pixel-perfect format based on the data
@@ -77,29 +203,97 @@ func isPixelFormat(level *Level) bool {
 	return level.BrickWidth == 0 && level.BrickHeight == 0
 }
 
-// ValidateLevel checks if a level has valid brick configurations
+// ValidateLevel checks if a level has valid brick configurations. Every problem
+// found is returned together as Problems.
 func ValidateLevel(level *Level) error {
+	src := level.src
+	var problems Problems
+	add := func(line int, format string, args ...interface{}) {
+		problems = append(problems, src.problem(line, format, args...))
+	}
+
 	if level.Name == "" {
-		return fmt.Errorf("level must have a name")
+		add(src.fieldLine("name"), "level must have a name")
 	}
 
 	if len(level.Bricks) == 0 {
-		return fmt.Errorf("level must have at least one brick")
+		add(src.fieldLine("bricks"), "level must have at least one brick")
+	}
+
+	if level.MinBallSpeed < 0 || level.MaxBallSpeed < 0 {
+		add(max(src.fieldLine("min_ball_speed"), src.fieldLine("max_ball_speed")),
+			"ball speed limits must not be negative (min=%.0f, max=%.0f)", level.MinBallSpeed, level.MaxBallSpeed)
+	} else if minSpeed, maxSpeed := level.SpeedLimits(); minSpeed > maxSpeed {
+		add(src.fieldLine("min_ball_speed"), "min_ball_speed (%.0f) exceeds max_ball_speed (%.0f)", minSpeed, maxSpeed)
+	}
+
+	if _, ok := themes.Lookup(level.Theme); level.Theme != "" && !ok {
+		add(src.fieldLine("theme"), "unknown theme %q%s", level.Theme, suggest(level.Theme, themes.IDs()))
+	}
+
+	if level.Music != "" && !slices.Contains(MusicFormats, strings.ToLower(path.Ext(level.Music))) {
+		add(src.fieldLine("music"), "music %q must be one of %s", level.Music, strings.Join(MusicFormats, ", "))
+	}
+
+	for i, brick := range level.Bricks {
+		line := src.brickLine(i)
+		if brick.Hits <= 0 {
+			add(line, "brick %d must have positive hits: %d", i, brick.Hits)
+		}
+
+		if brick.Type != "" && brick.BrickType != "" && brick.Type != brick.BrickType {
+			add(line, "brick %d sets both type %q and bricktype %q", i, brick.Type, brick.BrickType)
+		}
+		for _, name := range []string{brick.Type, brick.BrickType} {
+			if _, ok := entities.LookupBrickType(name); name != "" && !ok {
+				add(line, "brick %d has unknown brick type %q%s", i, name, suggest(name, entities.BrickTypeNames()))
+			}
+		}
+	}
+
+	if len(level.Bricks) > 0 && !slices.ContainsFunc(level.Bricks, breakable) {
+		add(src.fieldLine("bricks"), "level has only unbreakable bricks, so it can never be completed")
+	}
+
+	if level.UsePixelPositioning {
+		problems = append(problems, validatePixelBricks(level)...)
+	} else {
+		problems = append(problems, validateGridBricks(level)...)
 	}
 
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// validateGridBricks checks grid positions and that the brick field fits inside the gameplay area
+func validateGridBricks(level *Level) Problems {
+	src := level.src
+	var problems Problems
+
+	type cell struct{ x, y int }
+	seen := make(map[cell]int)
 	for i, brick := range level.Bricks {
+		line := src.brickLine(i)
 		if brick.X < 0 || brick.X >= entities.BrickCols {
-			return fmt.Errorf("brick %d has invalid X position: %d", i, brick.X)
+			problems = append(problems, src.problem(line, "brick %d has invalid X position: %d", i, brick.X))
 		}
 		if brick.Y < 0 || brick.Y >= entities.BrickRows {
-			return fmt.Errorf("brick %d has invalid Y position: %d", i, brick.Y)
+			problems = append(problems, src.problem(line, "brick %d has invalid Y position: %d", i, brick.Y))
 		}
-		if brick.Hits <= 0 {
-			return fmt.Errorf("brick %d must have positive hits: %d", i, brick.Hits)
+		if first, dup := seen[cell{brick.X, brick.Y}]; dup {
+			problems = append(problems, src.problem(line, "brick %d is at the same position as brick %d (%d, %d)", i, first, brick.X, brick.Y))
+		} else {
+			seen[cell{brick.X, brick.Y}] = i
 		}
+	}
+	if len(level.Bricks) == 0 {
+		return problems
+	}
 
-		// --- Gameplay-area bounds checks ---
-		// Determine horizontal field span (min/max X) to calculate pixel positions.
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, src.problem(src.fieldLine("bricks"), format, args...))
 	}
 
 	// Calculate brick field bounds (min & max X) for width validation.
@@ -126,14 +320,14 @@ func ValidateLevel(level *Level) error {
 	fieldColumns := maxX - minX + 1
 	fieldWidthPx := fieldColumns*level.BrickWidth + (fieldColumns-1)*level.BrickSpacingX
 	if float64(fieldWidthPx) > entities.GameAreaWidth {
-		return fmt.Errorf("brick field width (%d px) exceeds gameplay width (%.0f px)", fieldWidthPx, entities.GameAreaWidth)
+		add("brick field width (%d px) exceeds gameplay width (%.0f px)", fieldWidthPx, entities.GameAreaWidth)
 	}
 
 	// Horizontal centering offset (same calculation used by entities.Brick).
 	fieldStartX := entities.GameAreaLeft + (entities.GameAreaWidth-float64(fieldWidthPx))/2
 	fieldEndX := fieldStartX + float64(fieldWidthPx)
 	if fieldStartX < entities.GameAreaLeft || fieldEndX > entities.GameAreaRight {
-		return fmt.Errorf("brick field would render outside horizontal gameplay bounds (start=%.0f, end=%.0f)", fieldStartX, fieldEndX)
+		add("brick field would render outside horizontal gameplay bounds (start=%.0f, end=%.0f)", fieldStartX, fieldEndX)
 	}
 
 	// Vertical bounds: emulate entities.Brick.GetScreenPosition logic.
@@ -141,13 +335,59 @@ func ValidateLevel(level *Level) error {
 	topMostY := entities.GameAreaTop + float64(minY*verticalStride)
 	bottomMostY := entities.GameAreaTop + float64(maxY*verticalStride+level.BrickHeight)
 	if topMostY < entities.GameAreaTop {
-		return fmt.Errorf("top bricks would render above gameplay area (y=%.0f)", topMostY)
+		add("top bricks would render above gameplay area (y=%.0f)", topMostY)
 	}
 	if bottomMostY > entities.GameAreaBottom {
-		return fmt.Errorf("bottom bricks would render below gameplay area (y=%.0f)", bottomMostY)
+		add("bottom bricks would render below gameplay area (y=%.0f)", bottomMostY)
+	} else if bottomMostY > entities.PaddleLaneTop {
+		add("bottom bricks would reach into the paddle lane (y=%.0f, lane starts at %.0f)", bottomMostY, entities.PaddleLaneTop)
 	}
 
-	return nil
+	return problems
+}
+
+// validatePixelBricks checks every pixel-format brick against the gameplay area,
+// the paddle lane and the other bricks
+func validatePixelBricks(level *Level) Problems {
+	src := level.src
+	var problems Problems
+
+	type rect struct{ x, y, w, h float64 }
+	rects := make([]rect, len(level.Bricks))
+	laneTop := entities.PaddleLaneTop - entities.GameAreaTop // in gameplay-area coordinates
+	for i, brick := range level.Bricks {
+		line := src.brickLine(i)
+		x, y, width, height := brick.PixelBounds(level.DefaultBrickWidth, level.DefaultBrickHeight)
+		w, h := float64(width), float64(height)
+		rects[i] = rect{x, y, w, h}
+
+		if width <= 0 || height <= 0 {
+			problems = append(problems, src.problem(line, "brick %d must have a positive size: %dx%d", i, width, height))
+			continue
+		}
+		if x < 0 || y < 0 || x+w > entities.GameAreaWidth || y+h > entities.GameAreaHeight {
+			problems = append(problems, src.problem(line,
+				"brick %d at (%g, %g) size %dx%d lies outside the gameplay area (%.0fx%.0f)",
+				i, x, y, width, height, entities.GameAreaWidth, entities.GameAreaHeight))
+		} else if y+h > laneTop {
+			problems = append(problems, src.problem(line,
+				"brick %d reaches into the paddle lane (bottom y=%g, lane starts at y=%g)", i, y+h, laneTop))
+		}
+
+		for j := 0; j < i; j++ {
+			o := rects[j]
+			if o.w <= 0 || o.h <= 0 {
+				continue
+			}
+			if o.x == x && o.y == y {
+				problems = append(problems, src.problem(line, "brick %d is at the same position as brick %d (%g, %g)", i, j, x, y))
+			} else if x < o.x+o.w && o.x < x+w && y < o.y+o.h && o.y < y+h {
+				problems = append(problems, src.problem(line, "brick %d overlaps brick %d", i, j))
+			}
+		}
+	}
+
+	return problems
 }
 
 // AutoFitLevel modifies brick sizes so the field fits horizontally in GameAreaWidth
@@ -0,0 +1,331 @@
+package levels
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFile is the name of the manifest at the root of every level pack
+const ManifestFile = "pack.json"
+
+// BuiltinPackID identifies the levels shipped with the game
+const BuiltinPackID = "builtin"
+
+// Manifest describes a level pack and its ordered levels
+type Manifest struct {
+	Title   string          `json:"title"`
+	Author  string          `json:"author,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Levels  []ManifestLevel `json:"levels"`
+}
+
+// ManifestLevel is one entry of a pack's level list
+type ManifestLevel struct {
+	File  string `json:"file"`            // path of the level JSON relative to the pack root
+	Title string `json:"title,omitempty"` // display title; defaults to the level's name
+}
+
+// Pack is an ordered set of levels loaded from a directory, a zip archive or an fs.FS
+type Pack struct {
+	Manifest
+
+	id     string // short identifier, e.g. the directory or archive name
+	source string // where the pack was loaded from, for messages
+	fsys   fs.FS
+	dir    string // on-disk directory for writable packs, "" otherwise
+}
+
+// OpenPack reads a pack from fsys, which must contain pack.json at its root or
+// inside its single top-level directory.
+func OpenPack(fsys fs.FS, id, source string) (*Pack, error) {
+	fsys, err := packRoot(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("level pack %s: %v", source, err)
+	}
+
+	raw, err := fs.ReadFile(fsys, ManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("level pack %s: %v", source, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("level pack %s: failed to parse %s: %v", source, ManifestFile, err)
+	}
+
+	if m.Title == "" {
+		return nil, fmt.Errorf("level pack %s: manifest must have a title", source)
+	}
+	if len(m.Levels) == 0 {
+		return nil, fmt.Errorf("level pack %s: manifest lists no levels", source)
+	}
+	var missing []string
+	for _, lvl := range m.Levels {
+		if _, err := fs.Stat(fsys, lvl.File); err != nil {
+			missing = append(missing, lvl.File)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("level pack %s: missing level files: %s", source, strings.Join(missing, ", "))
+	}
+
+	return &Pack{Manifest: m, id: id, source: source, fsys: fsys}, nil
+}
+
+// packRoot returns fsys, or its single top-level directory if pack.json is there
+func packRoot(fsys fs.FS) (fs.FS, error) {
+	if _, err := fs.Stat(fsys, ManifestFile); err == nil {
+		return fsys, nil
+	}
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		sub, err := fs.Sub(fsys, entries[0].Name())
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fs.Stat(sub, ManifestFile); err == nil {
+			return sub, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s found", ManifestFile)
+}
+
+// OpenPackDir opens a pack stored in a directory. Directory packs are writable.
+func OpenPackDir(dir string) (*Pack, error) {
+	p, err := OpenPack(os.DirFS(dir), filepath.Base(dir), dir)
+	if err != nil {
+		return nil, err
+	}
+	p.dir = dir
+	return p, nil
+}
+
+// OpenPackZip opens a pack stored in a zip archive
+func OpenPackZip(file string) (*Pack, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("level pack %s: %v", file, err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("level pack %s: %v", file, err)
+	}
+	return OpenPack(zr, strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), file)
+}
+
+// OpenPackPath opens a directory or zip pack depending on what path points to
+func OpenPackPath(p string) (*Pack, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, fmt.Errorf("level pack %s: %v", p, err)
+	}
+	if info.IsDir() {
+		return OpenPackDir(p)
+	}
+	return OpenPackZip(p)
+}
+
+// BuiltinPack returns the levels shipped with the game. When run from the
+// source tree the levels/ directory is used so edits show up immediately;
+// otherwise the copy embedded in the binary is used.
+func BuiltinPack() (*Pack, error) {
+	if _, err := os.Stat(filepath.Join("levels", ManifestFile)); err == nil {
+		p, err := OpenPackDir("levels")
+		if err == nil {
+			p.id = BuiltinPackID
+		}
+		return p, err
+	}
+	return OpenPack(embeddedLevels, BuiltinPackID, "embedded levels")
+}
+
+// PackDirs returns the directories searched for installed packs: packs/ next to
+// the working directory and BRIX/packs in the user's config directory.
+func PackDirs() []string {
+	dirs := []string{"packs"}
+	if cfg, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(cfg, "BRIX", "packs"))
+	}
+	return dirs
+}
+
+// DiscoverPacks returns the builtin pack followed by every pack directory and
+// .zip archive found directly inside dirs. Packs that fail to open are
+// reported in the returned errors and skipped.
+func DiscoverPacks(dirs ...string) ([]*Pack, []error) {
+	var packs []*Pack
+	var errs []error
+
+	builtin, err := BuiltinPack()
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		packs = append(packs, builtin)
+	}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+		for _, entry := range entries {
+			full := filepath.Join(dir, entry.Name())
+			var p *Pack
+			switch {
+			case entry.IsDir():
+				p, err = OpenPackDir(full)
+			case strings.EqualFold(filepath.Ext(entry.Name()), ".zip"):
+				p, err = OpenPackZip(full)
+			default:
+				continue
+			}
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			packs = append(packs, p)
+		}
+	}
+
+	return packs, errs
+}
+
+// ID returns the pack's short identifier
+func (p *Pack) ID() string {
+	return p.id
+}
+
+// Source returns where the pack was loaded from
+func (p *Pack) Source() string {
+	return p.source
+}
+
+// Len returns the number of levels in the pack
+func (p *Pack) Len() int {
+	return len(p.Levels)
+}
+
+// Writable reports whether levels can be saved back into the pack
+func (p *Pack) Writable() bool {
+	return p.dir != ""
+}
+
+// LevelTitle returns the manifest title of a level, or "" if it has none
+func (p *Pack) LevelTitle(levelNum int) string {
+	if levelNum < 1 || levelNum > p.Len() {
+		return ""
+	}
+	return p.Levels[levelNum-1].Title
+}
+
+// LevelName returns a human-readable location of a level for messages
+func (p *Pack) LevelName(levelNum int) string {
+	if levelNum < 1 || levelNum > p.Len() {
+		return fmt.Sprintf("%s level %d", p.source, levelNum)
+	}
+	return path.Join(p.source, p.Levels[levelNum-1].File)
+}
+
+// ReadLevelData returns the raw JSON of a level (numbered from 1)
+func (p *Pack) ReadLevelData(levelNum int) ([]byte, error) {
+	if levelNum < 1 || levelNum > p.Len() {
+		return nil, fmt.Errorf("level pack %s has no level %d", p.source, levelNum)
+	}
+	data, err := fs.ReadFile(p.fsys, p.Levels[levelNum-1].File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read level file %s: %v", p.LevelName(levelNum), err)
+	}
+	return data, nil
+}
+
+// ReadLevel reads a level as written, without auto-fitting or validating it
+func (p *Pack) ReadLevel(levelNum int) (*Level, error) {
+	data, err := p.ReadLevelData(levelNum)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLevel(data, p.LevelName(levelNum))
+}
+
+// LoadLevel reads a level and prepares it for play
+func (p *Pack) LoadLevel(levelNum int) (*Level, error) {
+	level, err := p.ReadLevel(levelNum)
+	if err != nil {
+		return nil, err
+	}
+	if err := PrepareLevel(level, p.LevelName(levelNum)); err != nil {
+		return nil, err
+	}
+	if level.Music != "" {
+		if _, err := fs.Stat(p.fsys, level.Music); err != nil {
+			problem := level.src.problem(level.src.fieldLine("music"), "music file %q is not in the pack", level.Music)
+			problem.File = p.LevelName(levelNum)
+			return nil, Problems{problem}
+		}
+	}
+	return level, nil
+}
+
+// ReadFile reads a file from the pack, such as a level's music, by its slash-separated path
+func (p *Pack) ReadFile(name string) ([]byte, error) {
+	data, err := fs.ReadFile(p.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from level pack %s: %v", name, p.source, err)
+	}
+	return data, nil
+}
+
+// SaveLevel writes a level into a directory pack. Saving the level after the
+// last one appends it to the manifest as levelN.json.
+func (p *Pack) SaveLevel(levelNum int, level *Level) error {
+	if !p.Writable() {
+		return fmt.Errorf("level pack %s is read-only", p.source)
+	}
+	if levelNum < 1 || levelNum > p.Len()+1 {
+		return fmt.Errorf("level %d is out of range: pack %s has %d levels", levelNum, p.source, p.Len())
+	}
+
+	data, err := EncodeLevel(level)
+	if err != nil {
+		return fmt.Errorf("failed to encode level %d: %v", levelNum, err)
+	}
+
+	appending := levelNum == p.Len()+1
+	file := fmt.Sprintf("level%d.json", levelNum)
+	if !appending {
+		file = p.Levels[levelNum-1].File
+	}
+
+	full := filepath.Join(p.dir, filepath.FromSlash(file))
+	if err := os.WriteFile(full, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write level file %s: %v", full, err)
+	}
+
+	if appending {
+		p.Levels = append(p.Levels, ManifestLevel{File: file})
+		manifest, err := json.MarshalIndent(p.Manifest, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %v", ManifestFile, err)
+		}
+		if err := os.WriteFile(filepath.Join(p.dir, ManifestFile), append(manifest, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", ManifestFile, err)
+		}
+	}
+	return nil
+}
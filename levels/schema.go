@@ -0,0 +1,304 @@
+package levels
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"BRIX/entities"
+)
+
+// Problem is a single issue found in a level file
+type Problem struct {
+	File    string // level file name, empty when the level didn't come from a file
+	Line    int    // 1-based line number, 0 when unknown
+	Message string
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.File != "" && p.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	case p.File != "":
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	case p.Line > 0:
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// Problems collects every issue found in a level so they can be reported together
+type Problems []Problem
+
+func (ps Problems) Error() string {
+	lines := make([]string, len(ps))
+	for i, p := range ps {
+		lines[i] = p.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AsProblems extracts the problems from an error returned by this package.
+// Any other error becomes a single problem.
+func AsProblems(err error) Problems {
+	if err == nil {
+		return nil
+	}
+	var ps Problems
+	if errors.As(err, &ps) {
+		return ps
+	}
+	return Problems{{Message: err.Error()}}
+}
+
+// source remembers where a parsed level's fields and bricks were in its file
+type source struct {
+	file   string
+	data   []byte
+	fields map[string]int // line of each top-level key
+	bricks []int          // line each brick object starts on
+}
+
+// line converts a byte offset into a 1-based line number
+func (s *source) line(offset int64) int {
+	if offset > int64(len(s.data)) {
+		offset = int64(len(s.data))
+	}
+	return bytes.Count(s.data[:offset], []byte("\n")) + 1
+}
+
+// fieldLine returns the line of a top-level key, or 0 when unknown
+func (s *source) fieldLine(key string) int {
+	if s == nil {
+		return 0
+	}
+	return s.fields[key]
+}
+
+// brickLine returns the line brick i starts on, or 0 when unknown
+func (s *source) brickLine(i int) int {
+	if s == nil || i < 0 || i >= len(s.bricks) {
+		return 0
+	}
+	return s.bricks[i]
+}
+
+// problem builds a Problem located in the source file
+func (s *source) problem(line int, format string, args ...interface{}) Problem {
+	p := Problem{Line: line, Message: fmt.Sprintf(format, args...)}
+	if s != nil {
+		p.File = s.file
+	}
+	return p
+}
+
+// Keys a level file may use, taken from the json tags of the decoded types
+var (
+	levelFields = jsonFields(reflect.TypeOf(Level{}))
+	brickFields = jsonFields(reflect.TypeOf(entities.LevelBrick{}))
+)
+
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// decodeLevel walks the raw JSON of a level, recording where fields and bricks
+// are. Each value is decoded on its own, so an unknown key or a value of the
+// wrong type is reported without hiding problems further on. The level is nil
+// only when the JSON itself is broken.
+func decodeLevel(data []byte, file string) (*Level, Problems) {
+	src := &source{file: file, data: data, fields: make(map[string]int)}
+	level := &Level{src: src}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var problems Problems
+
+	syntax := func(err error) (*Level, Problems) {
+		return nil, append(problems, src.problem(src.line(dec.InputOffset()), "invalid JSON: %v", err))
+	}
+	// decode reads the next value into v's field for key, skipping keys v doesn't have
+	decode := func(v reflect.Value, key string, line int, prefix string) error {
+		f := fieldByKey(v, key)
+		if !f.IsValid() {
+			return skipValue(dec)
+		}
+		err := dec.Decode(f.Addr().Interface())
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			problems = append(problems, src.problem(line, "%s%s must be %s, not %s", prefix, key, typeErr.Type, typeErr.Value))
+			return nil
+		}
+		return err
+	}
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return syntax(err)
+	}
+	for dec.More() {
+		key, err := readKey(dec)
+		if err != nil {
+			return syntax(err)
+		}
+		line := src.line(dec.InputOffset())
+		if _, seen := src.fields[key]; seen {
+			problems = append(problems, src.problem(line, "duplicate field %q", key))
+		}
+		src.fields[key] = line
+
+		if !contains(levelFields, key) {
+			problems = append(problems, src.problem(line, "unknown field %q%s", key, suggest(key, levelFields)))
+		}
+		if key != "bricks" {
+			if err := decode(reflect.ValueOf(level).Elem(), key, line, ""); err != nil {
+				return syntax(err)
+			}
+			continue
+		}
+
+		level.Bricks, src.bricks = nil, nil
+		if tok, err := dec.Token(); err != nil {
+			return syntax(err)
+		} else if tok == nil {
+			continue // null bricks; reported by validation
+		} else if d, ok := tok.(json.Delim); !ok || d != '[' {
+			problems = append(problems, src.problem(line, `"bricks" must be an array`))
+			continue
+		}
+		for dec.More() {
+			if err := expectDelim(dec, '{'); err != nil {
+				return syntax(err)
+			}
+			src.bricks = append(src.bricks, src.line(dec.InputOffset()))
+			n := len(src.bricks) - 1
+			var brick entities.LevelBrick
+			for dec.More() {
+				key, err := readKey(dec)
+				if err != nil {
+					return syntax(err)
+				}
+				line := src.line(dec.InputOffset())
+				if !contains(brickFields, key) {
+					problems = append(problems, src.problem(line,
+						"brick %d: unknown field %q%s", n, key, suggest(key, brickFields)))
+				}
+				if err := decode(reflect.ValueOf(&brick).Elem(), key, line, fmt.Sprintf("brick %d: ", n)); err != nil {
+					return syntax(err)
+				}
+			}
+			if err := expectDelim(dec, '}'); err != nil {
+				return syntax(err)
+			}
+			level.Bricks = append(level.Bricks, brick)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return syntax(err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return syntax(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		problems = append(problems, src.problem(src.line(dec.InputOffset()), "unexpected data after the level object"))
+	}
+	return level, problems
+}
+
+// fieldByKey returns the field of struct v whose json key is key, or an invalid Value
+func fieldByKey(v reflect.Value, key string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if strings.Split(t.Field(i).Tag.Get("json"), ",")[0] == key {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %q but found %v", want, tok)
+	}
+	return nil
+}
+
+func readKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a field name but found %v", tok)
+	}
+	return key, nil
+}
+
+func skipValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns a "did you mean" hint for a misspelt name, or "" if nothing is close
+func suggest(name string, known []string) string {
+	best, bestDist := "", 3 // only suggest names within two edits
+	for _, k := range known {
+		d := editDistance(normalizeName(name), normalizeName(k))
+		if d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// normalizeName ignores case and separators so brick_type, brickType and bricktype compare equal
+func normalizeName(s string) string {
+	s = strings.ToLower(s)
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(s)
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
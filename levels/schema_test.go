@@ -0,0 +1,90 @@
+package levels
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testLevel is a valid pixel level; each case breaks one line of it
+const testLevel = `{
+  "name": "Test",
+  "use_pixel_positioning": true,
+  "ball_speed": 400,
+  "bricks": [
+    {"pixel_x": 100, "pixel_y": 100, "type": "standard", "hits": 1},
+    {"pixel_x": 300, "pixel_y": 100, "type": "standard", "hits": 2}
+  ]
+}
+`
+
+func TestParseLevelProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string // replaced in testLevel
+		want     []Problem
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "unknown field",
+			old:  `"ball_speed": 400,`,
+			new:  `"ball_speed": 400, "bal_speed": 300,`,
+			want: []Problem{{Line: 4, Message: `unknown field "bal_speed" (did you mean "ball_speed"?)`}},
+		},
+		{
+			name: "unknown brick field",
+			old:  `"type": "standard", "hits": 2}`,
+			new:  `"type": "standard", "hits": 2, "colour": "red"}`,
+			want: []Problem{{Line: 7, Message: `brick 1: unknown field "colour"`}},
+		},
+		{
+			name: "type error",
+			old:  `"ball_speed": 400,`,
+			new:  `"ball_speed": "fast",`,
+			want: []Problem{{Line: 4, Message: "ball_speed must be float64, not string"}},
+		},
+		{
+			name: "validation error",
+			old:  `"type": "standard", "hits": 2}`,
+			new:  `"type": "standrd", "hits": 2}`,
+			want: []Problem{{Line: 7, Message: `brick 1 has unknown brick type "standrd" (did you mean "standard"?)`}},
+		},
+		{
+			name: "every problem at once",
+			old:  `"name": "Test",`,
+			new:  `"name": "", "ball_speed": "fast", "colour": "red",`,
+			want: []Problem{
+				{Line: 2, Message: "ball_speed must be float64, not string"},
+				{Line: 2, Message: `unknown field "colour"`},
+				{Line: 2, Message: "level must have a name"},
+				{Line: 4, Message: "duplicate field \"ball_speed\""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := strings.Replace(testLevel, tt.old, tt.new, 1)
+			level, err := ParseLevel([]byte(data), "test.json")
+			if err == nil {
+				err = PrepareLevel(level, "test.json")
+			}
+
+			got := AsProblems(err)
+			for i := range got {
+				if got[i].File != "test.json" {
+					t.Errorf("problem %q is in file %q, want test.json", got[i].Message, got[i].File)
+				}
+				got[i].File = ""
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual([]Problem(got), tt.want) {
+				t.Errorf("problems:\n%v\nwant:\n%v", got, Problems(tt.want))
+			}
+		})
+	}
+}
@@ -1,15 +1,25 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
 
 	"BRIX/config"
 	"BRIX/game"
+	"BRIX/themes"
 )
 
 func main() {
+	dev := flag.Bool("dev", false, "development mode: reload levels, config and sprites when their files change")
+	configDir := flag.String("config", "", "directory of brick_types.json/scoring.json overrides applied over the defaults")
+	pack := flag.String("pack", "", "play the level pack in this directory or zip file")
+	record := flag.String("record", "", "record the session's input to this replay file")
+	replayPath := flag.String("replay", "", "play back a replay file recorded with -record")
+	sprites := flag.String("sprites", "", "use the sprite pack in this directory or zip file instead of the default sprites")
+	flag.Parse()
+
 	ebiten.SetWindowSize(1440, 1080)
 	ebiten.SetWindowTitle("BRIX - Brick Breaker Game")
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
@@ -20,14 +30,32 @@ func main() {
 	maxW, maxH := 2560, 1920 // 4:3 maximum
 	ebiten.SetWindowSizeLimits(minW, minH, maxW, maxH)
 
-	// Load brick & scoring configs
-	if err := config.Load(); err != nil {
+	// Load brick & scoring configs: embedded defaults, then user and -config overrides
+	var overrides []string
+	if *configDir != "" {
+		overrides = append(overrides, *configDir)
+	}
+	if err := config.Load(overrides...); err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
+	for _, v := range config.Sources() {
+		if v.Source != config.DefaultsSource {
+			log.Printf("config: %s = %s (from %s)", v.Key, v.Value, v.Source)
+		}
+	}
 
-	g := game.NewGame()
+	// Themes refer to brick types, so they load after the config
+	if err := themes.Load(); err != nil {
+		log.Printf("failed to load themes: %v", err)
+	}
+
+	g := game.NewGame(game.Options{Dev: *dev, ConfigDirs: overrides, PackPath: *pack, RecordPath: *record, ReplayPath: *replayPath, Sprites: *sprites})
 
-	if err := ebiten.RunGame(g); err != nil {
-		log.Fatal(err)
+	runErr := ebiten.RunGame(g)
+	if err := g.Close(); err != nil {
+		log.Printf("failed to save replay: %v", err)
+	}
+	if runErr != nil {
+		log.Fatal(runErr)
 	}
 }
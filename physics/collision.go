@@ -7,6 +7,10 @@ import (
 	"strconv"
 )
 
+// maxContactsPerTick bounds how many bounces are resolved in a single tick so a
+// ball wedged between surfaces can never stall the game loop.
+const maxContactsPerTick = 16
+
 // CollisionSystem handles all collision detection in the game
 type CollisionSystem struct{}
 
@@ -15,144 +19,222 @@ func NewCollisionSystem() *CollisionSystem {
 	return &CollisionSystem{}
 }
 
-// CheckPaddleCollision checks if the ball collides with the paddle
-func (cs *CollisionSystem) CheckPaddleCollision(ball *entities.Ball, paddle *entities.Paddle, score *int, lives int) {
-	if ball.VY() <= 0 {
-		return // ball moving upward, no collision possible
-	}
+// ContactKind identifies what a ball hit. Kinds are in order of precedence when
+// two hits happen at the same time.
+type ContactKind int
 
-	ballLeft, ballTop, ballRight, ballBottom := ball.GetBounds()
-	paddleLeft, paddleTop, paddleRight, paddleBottom := paddle.GetBounds()
+const (
+	contactNone ContactKind = iota
+	ContactWall
+	ContactPaddle
+	ContactBrick
+)
+
+// Contact is a collision resolved while moving a ball, for sound and effects
+type Contact struct {
+	Kind      ContactKind
+	X, Y      float64         // ball centre at the moment of contact
+	Brick     *entities.Brick // brick hit, for ContactBrick
+	Destroyed bool            // whether the brick was destroyed
+	Points    int             // points awarded for the contact
+}
 
-	// Check if ball overlaps with paddle
-	if ballBottom >= paddleTop && ballTop <= paddleBottom &&
-		ballRight >= paddleLeft && ballLeft <= paddleRight {
-		// Compute offset from paddle center (-1 .. 1)
-		offset := (ball.X() - paddle.X()) / (paddle.Width() / 2)
-		if offset < -1 {
-			offset = -1
+// MoveBall advances the ball through one entities.Tick with continuous collision
+// detection: it finds the earliest wall, paddle or brick contact along the ball's
+// path, resolves it, and continues the remaining motion, so fast balls can't
+// tunnel and several hits in one tick are handled in order. It returns the
+// contacts resolved during the tick.
+func (cs *CollisionSystem) MoveBall(ball *entities.Ball, paddle *entities.Paddle, bricks []*entities.Brick, score *int, lives int) []Contact {
+	var contacts []Contact
+
+	remaining := entities.Tick
+	stalled := false // the previous contact happened without the ball moving
+	for i := 0; i < maxContactsPerTick && remaining > 0; i++ {
+		dx, dy := ball.VX()*remaining, ball.VY()*remaining
+		r := ball.Radius()
+
+		best := hit{t: math.Inf(1)}
+		kind := contactNone
+		var hitBrick *entities.Brick
+
+		// Walls of the gameplay area (the bottom is open: that's where balls are lost)
+		if t, depth, ok := sweepWall(ball.X(), dx, entities.GameAreaLeft+r, -1); ok && t < best.t {
+			best, kind = hit{t: t, nx: 1, depth: depth}, ContactWall
 		}
-		if offset > 1 {
-			offset = 1
+		if t, depth, ok := sweepWall(ball.X(), dx, entities.GameAreaRight-r, 1); ok && t < best.t {
+			best, kind = hit{t: t, nx: -1, depth: depth}, ContactWall
 		}
-
-		// Maintain current speed magnitude but adjust direction
-		speed := math.Hypot(ball.VX(), ball.VY())
-		if speed == 0 {
-			speed = 240 // fallback speed
+		if t, depth, ok := sweepWall(ball.Y(), dy, entities.GameAreaTop+r, -1); ok && t < best.t {
+			best, kind = hit{t: t, ny: 1, depth: depth}, ContactWall
 		}
 
-		// Limit the horizontal component to prevent shallow bounces
-		// Max horizontal is 75% of speed, ensuring minimum 25% vertical
-		maxHorizontal := speed * 0.70
-		newVX := offset * maxHorizontal
-
-		// Ensure strong upward movement after bounce - minimum 50% of speed
-		minVertical := speed * 0.5
-		verticalFromHorizontal := math.Sqrt(speed*speed - newVX*newVX)
-		var newVY float64
-		if verticalFromHorizontal < minVertical {
-			newVY = -minVertical
-			// Recalculate horizontal to maintain speed
-			newVX = math.Copysign(math.Sqrt(speed*speed-newVY*newVY), newVX)
-		} else {
-			newVY = -verticalFromHorizontal
+		// Paddle, only while the ball is falling
+		if ball.VY() > 0 {
+			left, top, right, bottom := paddle.GetBounds()
+			if h, ok := sweepCircleRect(ball.X(), ball.Y(), dx, dy, r, left, top, right, bottom); ok && h.t < best.t {
+				best, kind = h, ContactPaddle
+			}
 		}
 
-		ball.SetVelocity(newVX, newVY)
-
-		key := strconv.Itoa(lives)
-		pts := config.Score.PaddleHit[key]
-		*score += pts
-	}
-}
-
-// CheckBrickCollisions checks if the ball collides with any bricks
-func (cs *CollisionSystem) CheckBrickCollisions(ball *entities.Ball, bricks []*entities.Brick, score *int, lives int) {
-	ballLeft, ballTop, ballRight, ballBottom := ball.GetBounds()
-	// _ = lives // (lives still used in scoring calc)
-
-	for _, brick := range bricks {
-		if !brick.IsActive() {
-			continue
+		// Bricks
+		for _, brick := range bricks {
+			if !brick.IsActive() {
+				continue
+			}
+			left, top, right, bottom := brick.GetBounds()
+			if h, ok := sweepCircleRect(ball.X(), ball.Y(), dx, dy, r, left, top, right, bottom); ok && h.t < best.t {
+				best, kind, hitBrick = h, ContactBrick, brick
+			}
 		}
 
-		brickLeft, brickTop, brickRight, brickBottom := brick.GetBounds()
-
-		// Check if ball overlaps with brick
-		if ballRight >= brickLeft && ballLeft <= brickRight &&
-			ballBottom >= brickTop && ballTop <= brickBottom {
-
-			// Hit the brick
-			destroyed := brick.Hit()
-
-			livesKey := strconv.Itoa(lives)
-			brickKey := string(brick.Type())
+		if kind == contactNone {
+			ball.Advance(remaining)
+			break
+		}
 
-			hitPts := config.Score.BrickHit[brickKey][livesKey]
-			destroyPts := config.Score.BrickDestroy[brickKey][livesKey]
+		// Move up to the contact point, push out of any overlap, then resolve it
+		ball.Advance(remaining * best.t)
+		remaining -= remaining * best.t
+		if best.depth > 0 {
+			ball.SetPosition(ball.X()+best.nx*best.depth, ball.Y()+best.ny*best.depth)
+			keepInField(ball, paddle)
+		}
 
-			if destroyed {
-				*score += destroyPts
+		c := Contact{Kind: kind, X: ball.X(), Y: ball.Y()}
+		switch kind {
+		case ContactWall:
+			reflect(ball, best.nx, best.ny)
+		case ContactPaddle:
+			if best.ny < 0 {
+				// Top face or the upper half of a rounded edge: angled bounce
+				c.Points = cs.bouncePaddle(ball, paddle, lives)
 			} else {
-				*score += hitPts
+				// Side or underside: the paddle only deflects the ball
+				reflect(ball, best.nx, best.ny)
+			}
+		case ContactBrick:
+			c.Brick = hitBrick
+			c.Destroyed, c.Points = cs.hitBrick(ball, hitBrick, best, lives)
+		}
+		*score += c.Points
+		contacts = append(contacts, c)
+
+		// Two contacts in a row without moving means the ball is pinned; leave
+		// the rest of the tick so it can't spend every contact in place
+		if best.t == 0 {
+			if stalled {
+				break
 			}
+			stalled = true
+		} else {
+			stalled = false
+		}
+	}
 
-			// Determine collision direction and bounce ball
-			cs.resolveBrickCollision(ball, brickLeft, brickTop, brickRight, brickBottom)
+	return contacts
+}
 
-			// Only handle one collision per frame
-			break
-		}
+// keepInField moves a ball that was pushed out of a surface back inside the
+// walls. A ball squeezed between the paddle and a wall has nowhere to go, so it
+// is sent straight down past the paddle.
+func keepInField(ball *entities.Ball, paddle *entities.Paddle) {
+	r := ball.Radius()
+	x := math.Max(entities.GameAreaLeft+r, math.Min(entities.GameAreaRight-r, ball.X()))
+	y := math.Max(entities.GameAreaTop+r, ball.Y())
+	if x == ball.X() && y == ball.Y() {
+		return
+	}
+	ball.SetPosition(x, y)
+
+	left, top, right, bottom := paddle.GetBounds()
+	if _, wedged := circleRectPenetration(x, y, r, left, top, right, bottom); wedged {
+		ball.SetVelocity(0, ball.Speed())
 	}
 }
 
-// CheckWallCollisions checks if the ball collides with gameplay area boundaries
-func (cs *CollisionSystem) CheckWallCollisions(ball *entities.Ball) {
-	ballLeft, ballTop, ballRight, _ := ball.GetBounds()
+// reflect mirrors the ball's velocity about the contact normal if it is moving into the surface
+func reflect(ball *entities.Ball, nx, ny float64) {
+	dot := ball.VX()*nx + ball.VY()*ny
+	if dot >= 0 {
+		return
+	}
+	ball.SetVelocity(ball.VX()-2*dot*nx, ball.VY()-2*dot*ny)
+}
 
-	// Left and right walls of gameplay area
-	if ballLeft <= entities.GameAreaLeft && ball.VX() < 0 {
-		ball.ReverseX()
+// bouncePaddle sends the ball back up at an angle that depends on where it hit
+// the paddle and returns the points for the hit
+func (cs *CollisionSystem) bouncePaddle(ball *entities.Ball, paddle *entities.Paddle, lives int) int {
+	// Compute offset from paddle center (-1 .. 1)
+	offset := (ball.X() - paddle.X()) / (paddle.Width() / 2)
+	if offset < -1 {
+		offset = -1
 	}
-	if ballRight >= entities.GameAreaRight && ball.VX() > 0 {
-		ball.ReverseX()
+	if offset > 1 {
+		offset = 1
 	}
 
-	// Top wall of gameplay area
-	if ballTop <= entities.GameAreaTop && ball.VY() < 0 {
-		ball.ReverseY()
+	// Maintain the ball's authoritative speed but adjust direction
+	speed := ball.Speed()
+	if speed == 0 {
+		speed = 240 // fallback speed
 	}
 
-	// Note: We don't handle bottom wall here as that's handled as "ball lost" in game logic
+	// Limit the horizontal component to prevent shallow bounces
+	// Max horizontal is 75% of speed, ensuring minimum 25% vertical
+	maxHorizontal := speed * 0.70
+	newVX := offset * maxHorizontal
+
+	// Ensure strong upward movement after bounce - minimum 50% of speed
+	minVertical := speed * 0.5
+	verticalFromHorizontal := math.Sqrt(speed*speed - newVX*newVX)
+	var newVY float64
+	if verticalFromHorizontal < minVertical {
+		newVY = -minVertical
+		// Recalculate horizontal to maintain speed
+		newVX = math.Copysign(math.Sqrt(speed*speed-newVY*newVY), newVX)
+	} else {
+		newVY = -verticalFromHorizontal
+	}
+
+	ball.SetVelocity(newVX, newVY)
+
+	key := strconv.Itoa(lives)
+	return config.Score.PaddleHit[key]
 }
 
-// resolveBrickCollision determines the appropriate bounce direction for brick collisions
-func (cs *CollisionSystem) resolveBrickCollision(ball *entities.Ball, brickLeft, brickTop, brickRight, brickBottom float64) {
-	ballX, ballY := ball.X(), ball.Y()
+// hitBrick damages the brick, bounces the ball off the contact normal and
+// applies the brick type's speed factor. It reports whether the brick was
+// destroyed and the points awarded.
+func (cs *CollisionSystem) hitBrick(ball *entities.Ball, brick *entities.Brick, h hit, lives int) (bool, int) {
+	destroyed := brick.Hit()
 
-	// Calculate distances to each edge
-	distLeft := ballX - brickLeft
-	distRight := brickRight - ballX
-	distTop := ballY - brickTop
-	distBottom := brickBottom - ballY
+	livesKey := strconv.Itoa(lives)
+	cfg := brick.Type().Config()
 
-	// Find the minimum distance to determine collision side
-	minDist := distLeft
-	if distRight < minDist {
-		minDist = distRight
-	}
-	if distTop < minDist {
-		minDist = distTop
+	pts := cfg.Points.Hit[livesKey]
+	if destroyed {
+		pts = cfg.Points.Destroy[livesKey]
 	}
-	if distBottom < minDist {
-		minDist = distBottom
+
+	reflect(ball, h.nx, h.ny)
+
+	// Speed the ball up (or down) by the brick type's factor; the ball clamps to its limits
+	if factor := cfg.SpeedFactor; factor > 0 {
+		ball.ScaleSpeed(factor)
 	}
 
-	// Bounce based on which side was hit
-	if minDist == distLeft || minDist == distRight {
-		ball.ReverseX()
-	} else {
-		ball.ReverseY()
+	return destroyed, pts
+}
+
+// CheckCapsuleCollision checks if the paddle catches a falling power-up capsule
+func (cs *CollisionSystem) CheckCapsuleCollision(capsule *entities.Capsule, paddle *entities.Paddle) bool {
+	if !capsule.IsActive() {
+		return false
 	}
+
+	capLeft, capTop, capRight, capBottom := capsule.GetBounds()
+	paddleLeft, paddleTop, paddleRight, paddleBottom := paddle.GetBounds()
+
+	return capBottom >= paddleTop && capTop <= paddleBottom &&
+		capRight >= paddleLeft && capLeft <= paddleRight
 }
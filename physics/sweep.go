@@ -0,0 +1,150 @@
+package physics
+
+import "math"
+
+// hit describes the earliest contact found by a sweep
+type hit struct {
+	t      float64 // fraction of the motion at which contact happens (0..1)
+	nx, ny float64 // unit contact normal pointing away from the surface
+	depth  float64 // penetration to push out along the normal when already overlapping
+}
+
+// sweepCircleRect sweeps a circle of radius r from (x, y) along (dx, dy) against
+// the rectangle. The circle touches the rectangle exactly when its centre
+// touches the rectangle grown by r with rounded corners, so faces give
+// axis-aligned normals and corners give the normal from the corner to the
+// centre. A circle that already overlaps and keeps moving inwards reports
+// contact at t=0 together with the depth needed to push it back out; one moving
+// away is ignored so it can leave cleanly.
+func sweepCircleRect(x, y, dx, dy, r, left, top, right, bottom float64) (hit, bool) {
+	if h, overlapping := circleRectPenetration(x, y, r, left, top, right, bottom); overlapping {
+		if dx*h.nx+dy*h.ny >= 0 {
+			return hit{}, false
+		}
+		return h, true
+	}
+
+	// Slab test against the grown box; it bounds the rounded shape
+	l, t, rt, b := left-r, top-r, right+r, bottom+r
+	txEnter, txExit, nx := slab(x, dx, l, rt)
+	tyEnter, tyExit, ny := slab(y, dy, t, b)
+
+	enter := math.Max(txEnter, tyEnter)
+	exit := math.Min(txExit, tyExit)
+	if enter > exit || exit < 0 || enter > 1 {
+		return hit{}, false
+	}
+
+	// Starting inside the grown box without touching means starting in a corner
+	// square, where only the rounded corner can be reached. Otherwise the entry
+	// point is a face hit unless it too lies in a corner square.
+	px, py := x, y
+	if enter >= 0 {
+		px, py = x+dx*enter, y+dy*enter
+		inX := px >= left && px <= right
+		inY := py >= top && py <= bottom
+		if inX || inY {
+			if txEnter > tyEnter {
+				return hit{t: enter, nx: nx}, true
+			}
+			return hit{t: enter, ny: ny}, true
+		}
+	}
+
+	// Corner region: sweep against the circle of radius r around the nearest corner
+	cx := math.Max(left, math.Min(right, px))
+	cy := math.Max(top, math.Min(bottom, py))
+	tc, ok := sweepCirclePoint(x, y, dx, dy, r, cx, cy)
+	if !ok {
+		return hit{}, false // passes the rounded corner
+	}
+	hx, hy := x+dx*tc-cx, y+dy*tc-cy
+	return hit{t: tc, nx: hx / r, ny: hy / r}, true
+}
+
+// circleRectPenetration reports whether a circle overlaps the rectangle and, if
+// so, the normal and depth that would separate them.
+func circleRectPenetration(x, y, r, left, top, right, bottom float64) (hit, bool) {
+	// Closest point on the rectangle to the circle's centre
+	cx := math.Max(left, math.Min(right, x))
+	cy := math.Max(top, math.Min(bottom, y))
+	ddx, ddy := x-cx, y-cy
+	dist := math.Hypot(ddx, ddy)
+
+	if dist >= r {
+		return hit{}, false
+	}
+	if dist > 0 {
+		return hit{nx: ddx / dist, ny: ddy / dist, depth: r - dist}, true
+	}
+
+	// Centre is inside the rectangle: leave through the nearest face
+	h := hit{nx: -1, depth: x - left + r}
+	if d := right - x + r; d < h.depth {
+		h = hit{nx: 1, depth: d}
+	}
+	if d := y - top + r; d < h.depth {
+		h = hit{ny: -1, depth: d}
+	}
+	if d := bottom - y + r; d < h.depth {
+		h = hit{ny: 1, depth: d}
+	}
+	return h, true
+}
+
+// sweepCirclePoint returns the earliest t in [0, 1] at which a point moving from
+// (x, y) by (dx, dy) comes within r of (cx, cy).
+func sweepCirclePoint(x, y, dx, dy, r, cx, cy float64) (float64, bool) {
+	fx, fy := x-cx, y-cy
+	a := dx*dx + dy*dy
+	if a == 0 {
+		return 0, false
+	}
+	b := 2 * (fx*dx + fy*dy)
+	c := fx*fx + fy*fy - r*r
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return 0, false
+	}
+	t := (-b - math.Sqrt(disc)) / (2 * a)
+	if t < 0 || t > 1 {
+		return 0, false
+	}
+	return t, true
+}
+
+// slab returns the entry and exit times of a 1D motion from p by d through [lo, hi]
+// and the normal of the face entered.
+func slab(p, d, lo, hi float64) (enter, exit, normal float64) {
+	if d == 0 {
+		if p < lo || p > hi {
+			return math.Inf(1), math.Inf(-1), 0 // never inside
+		}
+		return math.Inf(-1), math.Inf(1), 0 // always inside
+	}
+	enter, exit = (lo-p)/d, (hi-p)/d
+	normal = -1
+	if d < 0 {
+		enter, exit = exit, enter
+		normal = 1
+	}
+	return enter, exit, normal
+}
+
+// sweepWall sweeps a 1D coordinate p moving by d towards a wall at limit.
+// dir is +1 for a wall above p (p must stay <= limit) and -1 for one below.
+// A coordinate already past the wall reports contact at t=0 with the depth
+// needed to push it back to the limit.
+func sweepWall(p, d, limit, dir float64) (t, depth float64, ok bool) {
+	if d*dir <= 0 {
+		return 0, 0, false // moving away from the wall
+	}
+	if (limit-p)*dir <= 0 {
+		return 0, (p - limit) * dir, true // already touching or past it
+	}
+	t = (limit - p) / d
+	if t > 1 {
+		return 0, 0, false
+	}
+	return t, 0, true
+}
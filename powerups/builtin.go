@@ -0,0 +1,68 @@
+package powerups
+
+import "math"
+
+func init() {
+	Register(expandPaddle{})
+	Register(slowBall{})
+	Register(multiBall{})
+}
+
+// expandPaddle widens the paddle for a while
+type expandPaddle struct{}
+
+const expandFactor = 1.5
+
+func (expandPaddle) Name() string      { return "expand-paddle" }
+func (expandPaddle) Duration() float64 { return 10 }
+
+func (expandPaddle) Apply(t Target) {
+	t.Paddle().SetWidth(t.Paddle().Width() * expandFactor)
+}
+
+func (expandPaddle) Revert(t Target) {
+	t.Paddle().SetWidth(t.Paddle().Width() / expandFactor)
+}
+
+// slowBall slows every ball down for a while. Each ball remembers its speed
+// from before, so the level's speed limits can't make the slow-down permanent
+// and balls added while it is active aren't sped up when it ends.
+type slowBall struct{}
+
+const slowFactor = 0.7
+
+func (slowBall) Name() string      { return "slow-ball" }
+func (slowBall) Duration() float64 { return 8 }
+
+func (slowBall) Apply(t Target) {
+	for _, ball := range t.Balls() {
+		ball.HoldSpeed()
+		ball.ScaleSpeed(slowFactor)
+	}
+}
+
+func (slowBall) Revert(t Target) {
+	for _, ball := range t.Balls() {
+		ball.RestoreSpeed()
+	}
+}
+
+// multiBall instantly splits every ball into three
+type multiBall struct{}
+
+const multiBallSpread = math.Pi / 9 // 20° either side of the original direction
+
+func (multiBall) Name() string      { return "multi-ball" }
+func (multiBall) Duration() float64 { return 0 }
+
+func (multiBall) Apply(t Target) {
+	for _, ball := range t.Balls() {
+		for _, angle := range []float64{-multiBallSpread, multiBallSpread} {
+			split := ball.Clone()
+			split.Rotate(angle)
+			t.AddBall(split)
+		}
+	}
+}
+
+func (multiBall) Revert(t Target) {}
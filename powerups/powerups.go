@@ -0,0 +1,132 @@
+// Package powerups implements the timed effects granted by catching capsules.
+// Effects are looked up by the name used in brick_types.json's "powerUp" field,
+// so adding a new power-up only requires registering an Effect.
+package powerups
+
+import (
+	"fmt"
+	"sort"
+
+	"BRIX/entities"
+)
+
+// Target is the part of the game world an effect may modify
+type Target interface {
+	Paddle() *entities.Paddle
+	Balls() []*entities.Ball
+	AddBall(ball *entities.Ball)
+}
+
+// Effect is a power-up that changes the world while it is active
+type Effect interface {
+	// Name is the identifier used in brick_types.json and scoring.json
+	Name() string
+	// Duration is how long the effect lasts in seconds; 0 means it never expires
+	Duration() float64
+	// Apply starts the effect
+	Apply(t Target)
+	// Revert undoes Apply when the effect expires or is cleared
+	Revert(t Target)
+}
+
+var registry = map[string]Effect{}
+
+// Register makes an effect available by name. It panics if the name is already taken.
+func Register(e Effect) {
+	if _, dup := registry[e.Name()]; dup {
+		panic(fmt.Sprintf("powerups: effect %q registered twice", e.Name()))
+	}
+	registry[e.Name()] = e
+}
+
+// Lookup returns the effect registered under name
+func Lookup(name string) (Effect, bool) {
+	e, ok := registry[name]
+	return e, ok
+}
+
+// Names returns all registered effect names in sorted order
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Status describes an active effect
+type Status struct {
+	Name      string  `json:"name"`
+	Remaining float64 `json:"remaining"` // seconds left
+}
+
+type activeEffect struct {
+	effect    Effect
+	remaining float64
+}
+
+// Manager tracks active effects and reverts them when they expire
+type Manager struct {
+	active []*activeEffect
+}
+
+// NewManager creates an empty effect manager
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Activate applies e to t. Catching an effect that is already active refreshes
+// its timer instead of stacking it.
+func (m *Manager) Activate(e Effect, t Target) {
+	for _, a := range m.active {
+		if a.effect.Name() == e.Name() {
+			a.remaining = e.Duration()
+			return
+		}
+	}
+
+	e.Apply(t)
+	if e.Duration() > 0 {
+		m.active = append(m.active, &activeEffect{effect: e, remaining: e.Duration()})
+	}
+}
+
+// Resume reinstates an effect that is already applied to the target, such as
+// one restored from a saved game, so it reverts after remaining seconds
+func (m *Manager) Resume(e Effect, remaining float64) {
+	if e.Duration() > 0 && remaining > 0 {
+		m.active = append(m.active, &activeEffect{effect: e, remaining: remaining})
+	}
+}
+
+// Update counts active effects down by dt seconds and reverts expired ones
+func (m *Manager) Update(dt float64, t Target) {
+	kept := m.active[:0]
+	for _, a := range m.active {
+		a.remaining -= dt
+		if a.remaining <= 0 {
+			a.effect.Revert(t)
+			continue
+		}
+		kept = append(kept, a)
+	}
+	m.active = kept
+}
+
+// Clear reverts every active effect, e.g. when a life is lost or a level ends
+func (m *Manager) Clear(t Target) {
+	for _, a := range m.active {
+		a.effect.Revert(t)
+	}
+	m.active = nil
+}
+
+// Active returns the currently active effects
+func (m *Manager) Active() []Status {
+	out := make([]Status, len(m.active))
+	for i, a := range m.active {
+		out[i] = Status{Name: a.effect.Name(), Remaining: a.remaining}
+	}
+	return out
+}
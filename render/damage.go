@@ -0,0 +1,107 @@
+package render
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"BRIX/entities"
+)
+
+// flashTicks is how long a brick glows after a hit it survives (150ms)
+const flashTicks = 9
+
+// crackColor is drawn over damaged bricks whose type has no damage sprites
+var crackColor = color.RGBA{20, 20, 20, 200}
+
+// FlashBrick starts the hit flash of a brick that survived a hit
+func (r *Renderer) FlashBrick(b *entities.Brick) {
+	r.flashes[b] = flashTicks
+}
+
+// UpdateFlashes advances every hit flash by one tick and forgets finished ones.
+// Call it once per simulated tick so flashes keep pace with the game, not the clock.
+func (r *Renderer) UpdateFlashes() {
+	for b, ticks := range r.flashes {
+		if ticks <= 1 {
+			delete(r.flashes, b)
+		} else {
+			r.flashes[b] = ticks - 1
+		}
+	}
+}
+
+// ClearFlashes ends every hit flash, e.g. when the level ends
+func (r *Renderer) ClearFlashes() {
+	clear(r.flashes)
+}
+
+// brickSprite returns the sprite for a brick in its current damage state, and
+// whether that sprite already shows the damage. Only types without a theme
+// override use their damage sprites, since those are drawn to match the default.
+func (r *Renderer) brickSprite(b *entities.Brick) (*ebiten.Image, bool) {
+	img := r.brickImage(b.Type())
+	damage := b.Damage()
+	if damage == 0 {
+		return img, true
+	}
+	if _, themed := r.skin().bricks[b.Type()]; themed {
+		return img, false
+	}
+	stages := r.images.GetDamageImages(b.Type())
+	if len(stages) == 0 {
+		return img, false
+	}
+	return stages[damageStage(damage, len(stages))-1], true
+}
+
+// damageStage picks which of n damage sprites, from 1, shows a brick that has
+// lost a fraction damage of its hits. Any damage shows at least the first.
+func damageStage(damage float64, n int) int {
+	stage := int(math.Ceil(damage * float64(n)))
+	return max(1, min(stage, n))
+}
+
+// drawCracks draws cracks over a damaged brick, more as it loses more hits.
+// They are laid out from the brick's position so they stay put between frames
+// and the cracks of earlier hits remain as new ones appear.
+func (r *Renderer) drawCracks(screen *ebiten.Image, b *entities.Brick) {
+	left, top, right, bottom := b.GetBounds()
+	w, h := right-left, bottom-top
+
+	seed := uint32(int(left)*73856093 ^ int(top)*19349663)
+	next := func() float64 {
+		seed = seed*1664525 + 1013904223
+		return float64(seed>>8) / (1 << 24)
+	}
+
+	cracks := 1 + int(b.Damage()*4)
+	for i := 0; i < cracks; i++ {
+		// Each crack starts on the top or bottom edge and zig-zags into the brick
+		x, y, dir := left+w*(0.15+0.7*next()), top, 1.0
+		if next() < 0.5 {
+			y, dir = bottom, -1
+		}
+		for j := 0; j < 3; j++ {
+			nx := x + w*(next()-0.5)*0.25
+			ny := y + dir*h*(0.2+0.15*next())
+			vector.StrokeLine(screen, float32(x), float32(y), float32(nx), float32(ny), 1.5, crackColor, false)
+			x, y = nx, ny
+		}
+	}
+}
+
+// drawFlash brightens a brick that was hit within the last flashTicks, fading out
+func (r *Renderer) drawFlash(screen *ebiten.Image, b *entities.Brick) {
+	ticks, ok := r.flashes[b]
+	if !ok {
+		return
+	}
+	fade := float64(ticks) / flashTicks
+	left, top, right, bottom := b.GetBounds()
+	a := uint8(180 * fade)
+	vector.DrawFilledRect(screen, float32(left), float32(top), float32(right-left), float32(bottom-top),
+		color.RGBA{a, a, a, a}, false)
+}
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"BRIX/editor"
+	"BRIX/entities"
+)
+
+var (
+	editorSelectColor = color.RGBA{255, 220, 0, 255}
+	editorGuideColor  = color.RGBA{255, 255, 255, 40}
+)
+
+// DrawEditor draws the level editor: playfield, bricks, selection, palette and help
+func (r *Renderer) DrawEditor(screen *ebiten.Image, e *editor.Editor) {
+	screen.Fill(color.Black)
+
+	// Playfield background
+	r.drawBackground(screen)
+
+	// Grid rows in grid mode, and the paddle lane as a guide
+	if e.GridMode() {
+		level := e.PlaytestLevel()
+		stride := level.BrickHeight + level.BrickSpacingY
+		for row := 0; row <= entities.BrickRows && stride > 0; row++ {
+			y := float32(entities.GameAreaTop) + float32(row*stride)
+			vector.StrokeLine(screen, float32(entities.GameAreaLeft), y, float32(entities.GameAreaRight), y, 1, editorGuideColor, false)
+		}
+	}
+	vector.StrokeLine(screen, float32(entities.GameAreaLeft), float32(entities.PaddleLaneTop),
+		float32(entities.GameAreaRight), float32(entities.PaddleLaneTop), 2, editorGuideColor, false)
+
+	r.drawBricks(screen, e.Bricks())
+
+	// Selection outline and resize handle
+	if i := e.Selected(); i >= 0 && i < len(e.Bricks()) {
+		left, top, right, bottom := e.Bricks()[i].GetBounds()
+		vector.StrokeRect(screen, float32(left), float32(top), float32(right-left), float32(bottom-top), 3, editorSelectColor, false)
+		vector.DrawFilledRect(screen, float32(right)-14, float32(bottom)-14, 14, 14, editorSelectColor, false)
+	}
+
+	// HUD: level, format, hits and status on the left, palette on the right
+	vector.DrawFilledRect(screen, 0, 0, 1440, float32(entities.GameAreaTop), color.Black, false)
+	format := "PIXEL"
+	if e.GridMode() {
+		format = "GRID"
+	}
+	dirty := ""
+	if e.Dirty() {
+		dirty = " *"
+	}
+	r.drawText(screen, fmt.Sprintf("EDITOR  Level %d: %s  [%s]  Hits: %d%s",
+		e.LevelNum(), e.Level().Name, format, e.Hits(), dirty), 20, 25, color.White)
+	r.drawText(screen, e.Status(), 20, 50, color.RGBA{200, 200, 200, 255})
+
+	for i, t := range e.Palette() {
+		x, y, w, h := editor.PaletteRect(i)
+		img := r.brickImage(t)
+		pop := &ebiten.DrawImageOptions{}
+		b := img.Bounds()
+		pop.GeoM.Scale(w/float64(b.Dx()), h/float64(b.Dy()))
+		pop.GeoM.Translate(x, y)
+		screen.DrawImage(img, pop)
+
+		outline := color.Color(color.RGBA{255, 255, 255, 64})
+		if i == e.Current() {
+			outline = editorSelectColor
+		}
+		vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 2, outline, false)
+		r.drawText(screen, fmt.Sprintf("%d", i+1), int(x)+4, int(y)+20, color.White)
+	}
+
+	// Help lines along the bottom border
+	vector.DrawFilledRect(screen, 0, 1080-56, 1440, 56, color.RGBA{0, 0, 0, 200}, false)
+	r.drawText(screen, "Click: place/select   Drag: move   Corner: resize   Right-click/Del: delete   1-9/Tab: type   +/-: hits", 10, 1080-34, color.White)
+	r.drawText(screen, "G: grid/pixel   Ctrl+Z/Y: undo/redo   Ctrl+S: save   P: playtest   PgUp/PgDn: level   Esc: exit", 10, 1080-8, color.White)
+}
+
+// DrawPlaytestOverlay marks the screen as an editor playtest
+func (r *Renderer) DrawPlaytestOverlay(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, 0, 1080-28, 1440, 28, color.RGBA{0, 0, 0, 160}, false)
+	r.drawText(screen, "PLAYTEST  Esc: back to editor", 10, 1080-8, color.White)
+}
@@ -0,0 +1,43 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// DrawCampaignComplete draws the screen shown after the last level of a pack, with the end-of-run options
+func (r *Renderer) DrawCampaignComplete(screen *ebiten.Image, score int, options []string, choice int) {
+	screen.Fill(color.Black)
+
+	text.Draw(screen, "PACK COMPLETE", r.bigFont, 120, 300, color.White)
+	r.drawText(screen, "Every level cleared. Final score:", 120, 400, color.White)
+	text.Draw(screen, fmt.Sprintf("%d", score), r.bigFont, 120, 520, color.RGBA{255, 215, 0, 255})
+
+	r.drawEndOptions(screen, options, choice)
+}
+
+// drawEndOptions draws the end-of-run options in a row along the bottom of the screen
+func (r *Renderer) drawEndOptions(screen *ebiten.Image, options []string, choice int) {
+	if len(options) == 0 {
+		return
+	}
+
+	const y = 1000
+	gold := color.RGBA{255, 215, 0, 255}
+	x := 120
+	for i, label := range options {
+		width := text.BoundString(r.font, label).Dx()
+		if i == choice {
+			vector.DrawFilledRect(screen, float32(x-12), y-32, float32(width+24), 48, color.RGBA{255, 215, 0, 60}, false)
+			r.drawText(screen, label, x, y, gold)
+		} else {
+			r.drawText(screen, label, x, y, color.White)
+		}
+		x += width + 80
+	}
+	r.drawText(screen, "Left/Right: choose   Space/Enter: confirm   Esc: start screen", 120, 1080-16, color.RGBA{180, 180, 180, 255})
+}
@@ -0,0 +1,35 @@
+package render
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"BRIX/fx"
+)
+
+// DrawEffects draws the particles and score popups over the playfield
+func (r *Renderer) DrawEffects(screen *ebiten.Image, e *fx.Emitter) {
+	if !e.Enabled() {
+		return
+	}
+	for _, p := range e.Particles() {
+		switch p.Kind {
+		case fx.Trail:
+			vector.DrawFilledCircle(screen, float32(p.X), float32(p.Y), float32(p.Size), faded(p.Color, p.Fade()*0.35), false)
+		case fx.Spark:
+			vector.DrawFilledCircle(screen, float32(p.X), float32(p.Y), float32(p.Size), faded(p.Color, p.Fade()), false)
+		default:
+			vector.DrawFilledRect(screen, float32(p.X-p.Size/2), float32(p.Y-p.Size/2), float32(p.Size), float32(p.Size), faded(p.Color, p.Fade()), false)
+		}
+	}
+	for _, p := range e.Popups() {
+		r.drawText(screen, p.Text, int(p.X)-12, int(p.Y), faded(color.RGBA{255, 255, 255, 255}, p.Fade()))
+	}
+}
+
+// faded scales an opaque colour's opacity by f, keeping it premultiplied
+func faded(c color.RGBA, f float64) color.RGBA {
+	return color.RGBA{uint8(float64(c.R) * f), uint8(float64(c.G) * f), uint8(float64(c.B) * f), uint8(float64(c.A) * f)}
+}
@@ -0,0 +1,40 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"BRIX/levels"
+)
+
+// DrawPackList draws the installed level packs with the highlighted one marked
+func (r *Renderer) DrawPackList(screen *ebiten.Image, packs []*levels.Pack, selected int) {
+	screen.Fill(color.Black)
+
+	text.Draw(screen, "LEVEL PACKS", r.bigFont, 120, 160, color.White)
+
+	for i, p := range packs {
+		y := 240 + i*70
+		if i == selected {
+			vector.DrawFilledRect(screen, 100, float32(y-36), 1240, 60, color.RGBA{255, 255, 255, 40}, false)
+		}
+
+		title := p.Title
+		if p.Version != "" {
+			title += " v" + p.Version
+		}
+		r.drawText(screen, title, 120, y-10, color.White)
+
+		details := fmt.Sprintf("%d levels", p.Len())
+		if p.Author != "" {
+			details = "by " + p.Author + " - " + details
+		}
+		r.drawText(screen, details+"   "+p.Source(), 120, y+14, color.RGBA{180, 180, 180, 255})
+	}
+
+	r.drawText(screen, "Up/Down: choose   Enter: play   Esc: back", 120, 1080-40, color.White)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -15,6 +16,7 @@ import (
 
 	"BRIX/assets"
 	"BRIX/entities"
+	"BRIX/themes"
 )
 
 // Renderer handles all drawing operations
@@ -24,11 +26,17 @@ type Renderer struct {
 	bigFont font.Face
 
 	startTime time.Time // reference time for start-screen flash
+
+	theme *themes.Theme           // theme of the level being drawn, see SetTheme
+	skins map[*themes.Theme]*skin // decoded theme images
+
+	flashes map[*entities.Brick]int // ticks left in each recently hit brick's flash, see FlashBrick
 }
 
-// NewRenderer creates a new renderer with loaded images
-func NewRenderer() (*Renderer, error) {
-	images, err := assets.LoadImages()
+// NewRenderer creates a new renderer with loaded images, replacing the defaults
+// with those of the sprite pack at spritePack unless it is ""
+func NewRenderer(spritePack string) (*Renderer, error) {
+	images, err := assets.LoadImages(spritePack)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load images: %v", err)
 	}
@@ -83,6 +91,8 @@ func NewRenderer() (*Renderer, error) {
 		font:      fontFace,
 		bigFont:   bigFontFace,
 		startTime: time.Now(),
+		skins:     make(map[*themes.Theme]*skin),
+		flashes:   make(map[*entities.Brick]int),
 	}, nil
 }
 
@@ -91,14 +101,17 @@ func (r *Renderer) drawText(screen *ebiten.Image, str string, x, y int, clr colo
 	text.Draw(screen, str, r.font, x, y, clr)
 }
 
+// blink is true for the first 700ms of every second, for flashing screens and cursors
+func (r *Renderer) blink() bool {
+	return time.Since(r.startTime).Milliseconds()%1000 < 700
+}
+
 // DrawStartScreen draws the start screen
-func (r *Renderer) DrawStartScreen(screen *ebiten.Image, levelName string) {
+// canResume adds a hint for resuming a saved game.
+func (r *Renderer) DrawStartScreen(screen *ebiten.Image, levelName, packTitle string, canResume bool) {
 	// Decide which start image to show based on elapsed time in the current second
-	elapsed := time.Since(r.startTime)
-	ms := elapsed.Milliseconds() % 1000 // cycle every second
-
 	var img *ebiten.Image
-	if ms < 700 {
+	if r.blink() {
 		img = r.images.StartScreen1
 	} else {
 		img = r.images.StartScreen2
@@ -111,10 +124,18 @@ func (r *Renderer) DrawStartScreen(screen *ebiten.Image, levelName string) {
 	scaleY := 1080.0 / float64(bounds.Dy())
 	op.GeoM.Scale(scaleX, scaleY)
 	screen.DrawImage(img, op)
+
+	// Pack and menu hints along the bottom edge
+	vector.DrawFilledRect(screen, 0, 1080-28, 1440, 28, color.RGBA{0, 0, 0, 160}, false)
+	hints := fmt.Sprintf("%s   L: level packs   H: high scores   E: level editor", packTitle)
+	if canResume {
+		hints += "   R: resume saved game"
+	}
+	r.drawText(screen, hints, 10, 1080-8, color.White)
 }
 
 // DrawGame draws the main game screen
-func (r *Renderer) DrawGame(screen *ebiten.Image, paddle *entities.Paddle, ball *entities.Ball, bricks []*entities.Brick, levelName string, levelNum, score int, lives int) {
+func (r *Renderer) DrawGame(screen *ebiten.Image, paddle *entities.Paddle, balls []*entities.Ball, bricks []*entities.Brick, capsules []*entities.Capsule, levelName string, levelNum, score int, lives int) {
 	// Clear entire screen so borders remain black
 	screen.Fill(color.Black)
 
@@ -140,37 +161,35 @@ func (r *Renderer) DrawGame(screen *ebiten.Image, paddle *entities.Paddle, ball
 	// Bricks remaining
 	activeBricks := 0
 	for _, brick := range bricks {
-		if brick.IsActive() {
+		if brick.IsActive() && brick.Breakable() {
 			activeBricks++
 		}
 	}
 	bricksText := fmt.Sprintf("Bricks: %d", activeBricks)
 	r.drawText(screen, bricksText, 1200, 45, color.White)
 
-	// Playfield background using level-specific image (1400x1000)
-	backgroundImg := r.images.GetLevelBackground(levelNum)
-	op := &ebiten.DrawImageOptions{}
-
-	// Scale the background image to fit the 1400x1000 gameplay area
-	imgBounds := backgroundImg.Bounds()
-	scaleX := 1400.0 / float64(imgBounds.Dx())
-	scaleY := 1000.0 / float64(imgBounds.Dy())
-	op.GeoM.Scale(scaleX, scaleY)
-	op.GeoM.Translate(entities.GameAreaLeft, entities.GameAreaTop) // below HUD and with left border
-	screen.DrawImage(backgroundImg, op)
+	// Playfield background from the level's theme, scaled to the 1400x1000 gameplay area
+	r.drawBackground(screen)
 
 	// Draw bricks
 	r.drawBricks(screen, bricks)
 
+	// Draw falling power-up capsules
+	r.drawCapsules(screen, capsules)
+
 	// Draw paddle
 	r.drawPaddle(screen, paddle)
 
-	// Draw ball
-	r.drawBall(screen, ball)
+	// Draw balls
+	for _, ball := range balls {
+		r.drawBall(screen, ball)
+	}
 }
 
-// DrawGameOver draws the game over screen
-func (r *Renderer) DrawGameOver(screen *ebiten.Image, score int) {
+// DrawGameOver draws the game over screen with the end-of-run options, highlighting choice
+func (r *Renderer) DrawGameOver(screen *ebiten.Image, score int, options []string, choice int) {
+	defer r.drawEndOptions(screen, options, choice)
+
 	// Draw the game over screen image scaled to the window
 	img := r.images.GameOverScreen
 	if img == nil {
@@ -220,8 +239,8 @@ func (r *Renderer) DrawWaitingToContinue(screen *ebiten.Image, lives int) {
 	screen.DrawImage(img, op)
 }
 
-// DrawPauseScreen draws the pause screen
-func (r *Renderer) DrawPauseScreen(screen *ebiten.Image) {
+// DrawPauseScreen draws the pause screen, with a hint for saving when canSave is set
+func (r *Renderer) DrawPauseScreen(screen *ebiten.Image, canSave bool) {
 	// Draw the supplied pause screen image scaled to the window.
 	img := r.images.PauseScreen
 	if img == nil {
@@ -236,6 +255,18 @@ func (r *Renderer) DrawPauseScreen(screen *ebiten.Image) {
 	scaleY := 1080.0 / float64(bounds.Dy())
 	op.GeoM.Scale(scaleX, scaleY)
 	screen.DrawImage(img, op)
+
+	if canSave {
+		vector.DrawFilledRect(screen, 0, 1080-28, 1440, 28, color.RGBA{0, 0, 0, 160}, false)
+		r.drawText(screen, "S: save game and resume it later from the start screen", 10, 1080-8, color.White)
+	}
+}
+
+// DrawNotice draws a short message centred near the top of the screen
+func (r *Renderer) DrawNotice(screen *ebiten.Image, msg string) {
+	width := float32(text.BoundString(r.font, msg).Dx() + 40)
+	vector.DrawFilledRect(screen, (1440-width)/2, 90, width, 40, color.RGBA{0, 0, 0, 200}, false)
+	r.drawText(screen, msg, int(1440-width)/2+20, 117, color.White)
 }
 
 // DrawLevelComplete draws the level complete screen
@@ -255,16 +286,15 @@ func (r *Renderer) DrawLevelComplete(screen *ebiten.Image) {
 	screen.DrawImage(img, op)
 }
 
-// drawBricks draws all active bricks using sprite images
+// drawBricks draws all active bricks using sprite images, showing damage and hit flashes
 func (r *Renderer) drawBricks(screen *ebiten.Image, bricks []*entities.Brick) {
-
 	for _, brick := range bricks {
 		if !brick.IsActive() {
 			continue
 		}
 
 		brickX, brickY := brick.GetScreenPosition()
-		brickImg := r.images.GetBrickImage(brick.Type())
+		brickImg, showsDamage := r.brickSprite(brick)
 		brickWidth := float32(brick.Width())
 		brickHeight := float32(brick.Height())
 
@@ -279,6 +309,10 @@ func (r *Renderer) drawBricks(screen *ebiten.Image, bricks []*entities.Brick) {
 		op.GeoM.Translate(brickX, brickY)
 
 		screen.DrawImage(brickImg, op)
+		if !showsDamage {
+			r.drawCracks(screen, brick)
+		}
+		r.drawFlash(screen, brick)
 
 		// Draw white outline for better visibility (25% opacity)
 		vector.StrokeRect(screen, float32(brickX), float32(brickY),
@@ -293,17 +327,36 @@ func (r *Renderer) drawBricks(screen *ebiten.Image, bricks []*entities.Brick) {
 	}
 }
 
+// drawCapsules draws falling power-up capsules labelled with their effect's initial
+func (r *Renderer) drawCapsules(screen *ebiten.Image, capsules []*entities.Capsule) {
+	for _, capsule := range capsules {
+		if !capsule.IsActive() {
+			continue
+		}
+
+		left, top, _, _ := capsule.GetBounds()
+		vector.DrawFilledRect(screen, float32(left), float32(top),
+			entities.CapsuleWidth, entities.CapsuleHeight, color.RGBA{255, 200, 40, 255}, false)
+		vector.StrokeRect(screen, float32(left), float32(top),
+			entities.CapsuleWidth, entities.CapsuleHeight, 2.0, color.White, false)
+
+		label := strings.ToUpper(capsule.Kind()[:1])
+		r.drawText(screen, label, int(capsule.X())-6, int(capsule.Y())+7, color.Black)
+	}
+}
+
 // drawPaddle draws the paddle using sprite image
 func (r *Renderer) drawPaddle(screen *ebiten.Image, paddle *entities.Paddle) {
+	img := r.paddleImage()
 	op := &ebiten.DrawImageOptions{}
 
-	imgBounds := r.images.Paddle.Bounds()
+	imgBounds := img.Bounds()
 	scaleX := paddle.Width() / float64(imgBounds.Dx())
 	scaleY := paddle.Height() / float64(imgBounds.Dy())
 	op.GeoM.Scale(scaleX, scaleY)
 
 	op.GeoM.Translate(paddle.X()-paddle.Width()/2, paddle.Y())
-	screen.DrawImage(r.images.Paddle, op)
+	screen.DrawImage(img, op)
 }
 
 // drawBall draws the ball as a circle
@@ -311,3 +364,41 @@ func (r *Renderer) drawBall(screen *ebiten.Image, ball *entities.Ball) {
 	vector.DrawFilledCircle(screen, float32(ball.X()), float32(ball.Y()),
 		float32(ball.Radius()), color.White, false)
 }
+
+// ReloadImages replaces the sprites with those in dir, falling back to the
+// embedded copies for missing files; the sprite pack in use still takes
+// precedence. The current sprites are kept on error.
+func (r *Renderer) ReloadImages(dir string) error {
+	images, err := assets.LoadImagesFrom(dir, r.images.SpritePack())
+	if err != nil {
+		return err
+	}
+	r.images = images
+	r.skins = make(map[*themes.Theme]*skin)
+	return nil
+}
+
+// DrawErrorOverlay lists errors in a box across the top of the screen
+func (r *Renderer) DrawErrorOverlay(screen *ebiten.Image, lines []string) {
+	const lineHeight = 24
+	height := float32(len(lines)*lineHeight + 16)
+	vector.DrawFilledRect(screen, 0, 0, 1440, height, color.RGBA{120, 0, 0, 220}, false)
+	for i, line := range lines {
+		r.drawText(screen, line, 12, 28+i*lineHeight, color.White)
+	}
+}
+
+// DrawReplayOverlay draws the playback position and controls on top of the current screen
+func (r *Renderer) DrawReplayOverlay(screen *ebiten.Image, frame, total, speed int, paused bool) {
+	status := fmt.Sprintf("REPLAY %d/%d  %dx", frame, total, speed)
+	if paused {
+		status += "  PAUSED"
+	}
+	if frame >= total {
+		status += "  FINISHED"
+	}
+
+	vector.DrawFilledRect(screen, 0, 1080-40, 1440, 40, color.RGBA{0, 0, 0, 160}, false)
+	r.drawText(screen, status, 20, 1080-14, color.White)
+	r.drawText(screen, "F: fast-forward   P: pause   .: step", 1000, 1080-14, color.White)
+}
@@ -0,0 +1,57 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"BRIX/scores"
+)
+
+// DrawNameEntry draws the screen where the player types a name for a new high score
+func (r *Renderer) DrawNameEntry(screen *ebiten.Image, score int, name string) {
+	screen.Fill(color.Black)
+
+	text.Draw(screen, "NEW HIGH SCORE", r.bigFont, 120, 300, color.White)
+	text.Draw(screen, fmt.Sprintf("%d", score), r.bigFont, 120, 420, color.RGBA{255, 215, 0, 255})
+
+	r.drawText(screen, "Enter your name:", 120, 520, color.White)
+	vector.DrawFilledRect(screen, 120, 540, 520, 60, color.RGBA{255, 255, 255, 40}, false)
+	cursor := ""
+	if r.blink() {
+		cursor = "_"
+	}
+	text.Draw(screen, name+cursor, r.font, 136, 580, color.White)
+
+	r.drawText(screen, "Type a name, Backspace to correct, Enter to save", 120, 1080-40, color.White)
+}
+
+// DrawHighScores draws a pack's high-score table, highlighting one entry (-1 for none)
+func (r *Renderer) DrawHighScores(screen *ebiten.Image, packTitle string, entries []scores.Entry, highlight int) {
+	screen.Fill(color.Black)
+
+	text.Draw(screen, "HIGH SCORES", r.bigFont, 120, 160, color.White)
+	r.drawText(screen, packTitle, 120, 200, color.RGBA{180, 180, 180, 255})
+
+	if len(entries) == 0 {
+		r.drawText(screen, "No scores yet", 120, 280, color.White)
+	}
+	for i, e := range entries {
+		y := 280 + i*56
+		if i == highlight {
+			vector.DrawFilledRect(screen, 100, float32(y-32), 1240, 48, color.RGBA{255, 215, 0, 60}, false)
+		}
+		// Proportional font, so each column is drawn at its own x
+		r.drawText(screen, fmt.Sprintf("%d.", i+1), 120, y, color.White)
+		r.drawText(screen, e.Name, 180, y, color.White)
+		r.drawText(screen, fmt.Sprintf("%d", e.Score), 500, y, color.White)
+		r.drawText(screen, fmt.Sprintf("level %d", e.Level), 700, y, color.White)
+		r.drawText(screen, fmt.Sprintf("lives %d", e.Lives), 880, y, color.White)
+		r.drawText(screen, e.Date.Format("2006-01-02"), 1040, y, color.White)
+	}
+
+	r.drawText(screen, "Enter or Esc: back", 120, 1080-40, color.White)
+}
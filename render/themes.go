@@ -0,0 +1,104 @@
+package render
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"BRIX/assets"
+	"BRIX/entities"
+	"BRIX/themes"
+)
+
+// skin holds the decoded images of a theme; a nil image means the default sprite
+type skin struct {
+	background *ebiten.Image
+	tint       ebiten.ColorScale
+	bricks     map[entities.BrickType]*ebiten.Image
+	paddle     *ebiten.Image
+}
+
+// SetTheme selects the theme the playfield is drawn with; nil draws the default sprites
+func (r *Renderer) SetTheme(t *themes.Theme) {
+	r.theme = t
+}
+
+// skin returns the images of the current theme, decoding them on first use.
+// Files that can't be read or decoded are logged once and drawn with the defaults.
+func (r *Renderer) skin() *skin {
+	if r.theme == nil {
+		return &skin{}
+	}
+	if s, ok := r.skins[r.theme]; ok {
+		return s
+	}
+
+	t := r.theme
+	s := &skin{
+		background: loadThemeImage(t, t.Background),
+		bricks:     make(map[entities.BrickType]*ebiten.Image),
+		paddle:     loadThemeImage(t, t.Paddle),
+	}
+	if tint, ok := t.TintColor(); ok {
+		s.tint.ScaleWithColor(tint)
+	}
+	for name, file := range t.Bricks {
+		if brickType, ok := entities.LookupBrickType(name); ok {
+			if img := loadThemeImage(t, file); img != nil {
+				s.bricks[brickType] = img
+			}
+		}
+	}
+	r.skins[t] = s
+	return s
+}
+
+// loadThemeImage decodes an image file of a theme, or returns nil if there is none
+func loadThemeImage(t *themes.Theme, name string) *ebiten.Image {
+	if name == "" {
+		return nil
+	}
+	data, err := t.ReadFile(name)
+	if err != nil {
+		log.Printf("Failed to load theme image: %v", err)
+		return nil
+	}
+	img, err := assets.DecodeImage(data)
+	if err != nil {
+		log.Printf("Failed to decode theme %s image %s: %v", t.ID(), name, err)
+		return nil
+	}
+	return img
+}
+
+// drawBackground stretches the theme's background over the playfield, tinted
+func (r *Renderer) drawBackground(screen *ebiten.Image) {
+	s := r.skin()
+	img := s.background
+	if img == nil {
+		img = r.images.LevelBackground
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	imgBounds := img.Bounds()
+	op.GeoM.Scale(entities.GameAreaWidth/float64(imgBounds.Dx()), entities.GameAreaHeight/float64(imgBounds.Dy()))
+	op.GeoM.Translate(entities.GameAreaLeft, entities.GameAreaTop)
+	op.ColorScale.ScaleWithColorScale(s.tint)
+	screen.DrawImage(img, op)
+}
+
+// brickImage returns the sprite of a brick type, preferring the theme's
+func (r *Renderer) brickImage(t entities.BrickType) *ebiten.Image {
+	if img, ok := r.skin().bricks[t]; ok {
+		return img
+	}
+	return r.images.GetBrickImage(t)
+}
+
+// paddleImage returns the paddle sprite, preferring the theme's
+func (r *Renderer) paddleImage() *ebiten.Image {
+	if img := r.skin().paddle; img != nil {
+		return img
+	}
+	return r.images.Paddle
+}
@@ -0,0 +1,102 @@
+package replay
+
+import "BRIX/input"
+
+// MaxSpeed is the highest fast-forward multiplier
+const MaxSpeed = 8
+
+// Player plays a replay back as an input source and controls how many
+// simulation ticks run per rendered frame.
+type Player struct {
+	rep *Replay
+	src *input.Scripted
+
+	speed  int  // ticks per frame while playing
+	paused bool // no ticks unless a frame step is pending
+	steps  int  // pending single-frame steps
+}
+
+// NewPlayer creates a player positioned at the first frame
+func NewPlayer(rep *Replay) *Player {
+	return &Player{
+		rep:   rep,
+		src:   input.NewScripted(rep.Frames...),
+		speed: 1,
+	}
+}
+
+// Poll implements input.InputSource
+func (p *Player) Poll() input.Actions {
+	return p.src.Poll()
+}
+
+// Header returns the replay header
+func (p *Player) Header() Header {
+	return p.rep.Header
+}
+
+// TicksThisFrame returns how many simulation ticks to run for the current frame
+// and consumes any pending frame step.
+func (p *Player) TicksThisFrame() int {
+	remaining := p.src.Len() - p.src.Pos()
+	n := p.speed
+	if p.paused {
+		n = 0
+		if p.steps > 0 {
+			n = 1
+			p.steps--
+		}
+	}
+	if n > remaining {
+		n = remaining
+	}
+	return n
+}
+
+// CycleSpeed doubles the fast-forward multiplier, wrapping back to 1x
+func (p *Player) CycleSpeed() {
+	p.speed *= 2
+	if p.speed > MaxSpeed {
+		p.speed = 1
+	}
+}
+
+// TogglePause pauses or resumes playback
+func (p *Player) TogglePause() {
+	p.paused = !p.paused
+	p.steps = 0
+}
+
+// StepFrame advances a paused replay by exactly one tick
+func (p *Player) StepFrame() {
+	if !p.paused {
+		p.paused = true
+		return
+	}
+	p.steps++
+}
+
+// Speed returns the fast-forward multiplier
+func (p *Player) Speed() int {
+	return p.speed
+}
+
+// Paused reports whether playback is paused
+func (p *Player) Paused() bool {
+	return p.paused
+}
+
+// Frame returns the index of the next frame to play
+func (p *Player) Frame() int {
+	return p.src.Pos()
+}
+
+// Len returns the total number of recorded frames
+func (p *Player) Len() int {
+	return p.src.Len()
+}
+
+// Done reports whether every frame has been played
+func (p *Player) Done() bool {
+	return p.src.Done()
+}
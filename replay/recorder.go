@@ -0,0 +1,27 @@
+package replay
+
+import "BRIX/input"
+
+// Recorder wraps an input source and records every polled frame
+type Recorder struct {
+	src input.InputSource
+	rep *Replay
+}
+
+// NewRecorder starts recording src under the given header
+func NewRecorder(src input.InputSource, h Header) *Recorder {
+	return &Recorder{src: src, rep: &Replay{Header: h}}
+}
+
+// Poll implements input.InputSource. The returned actions are quantized so the
+// live run sees exactly what playback will see.
+func (r *Recorder) Poll() input.Actions {
+	a := Quantize(r.src.Poll())
+	r.rep.Frames = append(r.rep.Frames, a)
+	return a
+}
+
+// Replay returns the recording so far
+func (r *Recorder) Replay() *Replay {
+	return r.rep
+}
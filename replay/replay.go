@@ -0,0 +1,270 @@
+// Package replay records the per-tick input of a session and plays it back.
+// Because the simulation advances in fixed entities.Tick steps and has no
+// randomness, feeding the same actions into a world created with the same
+// options reproduces the exact same run.
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"BRIX/config"
+	"BRIX/input"
+	"BRIX/levels"
+)
+
+// Version is the current replay file format version. Version 1 files, which
+// predate level packs, are still read and play the builtin pack.
+const Version = 2
+
+// magic identifies replay files
+var magic = [4]byte{'B', 'R', 'X', 'R'}
+
+// Action flag bits in the encoded frame
+const (
+	flagLaunch = 1 << iota
+	flagPause
+	flagConfirm
+	flagBack
+)
+
+// Header describes the conditions a replay was recorded under
+type Header struct {
+	Version        int
+	PackID         string // level pack the run was played in
+	StartLevel     int
+	Seed           int64
+	BrickTypesHash string
+	ScoringHash    string
+	LevelHash      string // hash of the starting level
+}
+
+// NewHeader captures the current configuration for a run starting on level of the given pack
+func NewHeader(packID string, startLevel int, seed int64, level *levels.Level) Header {
+	return Header{
+		Version:        Version,
+		PackID:         packID,
+		StartLevel:     startLevel,
+		Seed:           seed,
+		BrickTypesHash: config.BrickTypesHash(),
+		ScoringHash:    config.ScoringHash(),
+		LevelHash:      levels.Hash(level),
+	}
+}
+
+// Check compares the header against the current configuration and returns a
+// description of every mismatch. A replay with mismatches may diverge.
+func (h Header) Check(level *levels.Level) []string {
+	var problems []string
+	if h.BrickTypesHash != config.BrickTypesHash() {
+		problems = append(problems, "brick types config differs from recording")
+	}
+	if h.ScoringHash != config.ScoringHash() {
+		problems = append(problems, "scoring config differs from recording")
+	}
+	if h.LevelHash != levels.Hash(level) {
+		problems = append(problems, fmt.Sprintf("level %d differs from recording", h.StartLevel))
+	}
+	return problems
+}
+
+// Replay is a recorded session: a header plus one Actions value per tick
+type Replay struct {
+	Header
+	Frames []input.Actions
+}
+
+// Quantize rounds the move axis to the precision stored on disk. Recorders
+// feed quantized actions into the simulation so playback sees identical values.
+func Quantize(a input.Actions) input.Actions {
+	a.Move = float64(encodeMove(a.Move)) / 127
+	return a
+}
+
+func encodeMove(move float64) int8 {
+	return int8(math.Round(math.Max(-1, math.Min(1, move)) * 127))
+}
+
+func encodeFlags(a input.Actions) byte {
+	var f byte
+	if a.Launch {
+		f |= flagLaunch
+	}
+	if a.Pause {
+		f |= flagPause
+	}
+	if a.Confirm {
+		f |= flagConfirm
+	}
+	if a.Back {
+		f |= flagBack
+	}
+	return f
+}
+
+func decodeFrame(flags byte, move int8) input.Actions {
+	return input.Actions{
+		Move:    float64(move) / 127,
+		Launch:  flags&flagLaunch != 0,
+		Pause:   flags&flagPause != 0,
+		Confirm: flags&flagConfirm != 0,
+		Back:    flags&flagBack != 0,
+	}
+}
+
+// Encode writes the replay in the compact binary format: a header followed by
+// run-length encoded frames (run length, action flags, quantized move axis).
+func (r *Replay) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var buf [binary.MaxVarintLen64]byte
+
+	putUvarint := func(v uint64) {
+		n := binary.PutUvarint(buf[:], v)
+		bw.Write(buf[:n])
+	}
+	putString := func(s string) {
+		putUvarint(uint64(len(s)))
+		bw.WriteString(s)
+	}
+
+	bw.Write(magic[:])
+	bw.WriteByte(Version)
+	putString(r.PackID)
+	putUvarint(uint64(r.StartLevel))
+	n := binary.PutVarint(buf[:], r.Seed)
+	bw.Write(buf[:n])
+	putString(r.BrickTypesHash)
+	putString(r.ScoringHash)
+	putString(r.LevelHash)
+	putUvarint(uint64(len(r.Frames)))
+
+	for i := 0; i < len(r.Frames); {
+		flags, move := encodeFlags(r.Frames[i]), encodeMove(r.Frames[i].Move)
+		run := 1
+		for i+run < len(r.Frames) &&
+			encodeFlags(r.Frames[i+run]) == flags && encodeMove(r.Frames[i+run].Move) == move {
+			run++
+		}
+		putUvarint(uint64(run))
+		bw.WriteByte(flags)
+		bw.WriteByte(byte(move))
+		i += run
+	}
+
+	return bw.Flush()
+}
+
+// Decode reads a replay written by Encode
+func Decode(r io.Reader) (*Replay, error) {
+	br := bufio.NewReader(r)
+
+	var m [4]byte
+	if _, err := io.ReadFull(br, m[:]); err != nil {
+		return nil, fmt.Errorf("read magic: %w", err)
+	}
+	if m != magic {
+		return nil, errors.New("not a BRIX replay file")
+	}
+	version, err := br.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("read version: %w", err)
+	}
+	if version < 1 || version > Version {
+		return nil, fmt.Errorf("unsupported replay version %d (want %d)", version, Version)
+	}
+
+	readString := func() (string, error) {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return "", err
+		}
+		if n > 256 {
+			return "", fmt.Errorf("header string too long (%d bytes)", n)
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(br, b); err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	rep := &Replay{Header: Header{Version: int(version), PackID: levels.BuiltinPackID}}
+	if version >= 2 {
+		if rep.PackID, err = readString(); err != nil {
+			return nil, fmt.Errorf("read pack id: %w", err)
+		}
+	}
+	startLevel, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("read start level: %w", err)
+	}
+	rep.StartLevel = int(startLevel)
+	if rep.Seed, err = binary.ReadVarint(br); err != nil {
+		return nil, fmt.Errorf("read seed: %w", err)
+	}
+	if rep.BrickTypesHash, err = readString(); err != nil {
+		return nil, fmt.Errorf("read brick types hash: %w", err)
+	}
+	if rep.ScoringHash, err = readString(); err != nil {
+		return nil, fmt.Errorf("read scoring hash: %w", err)
+	}
+	if rep.LevelHash, err = readString(); err != nil {
+		return nil, fmt.Errorf("read level hash: %w", err)
+	}
+
+	total, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("read frame count: %w", err)
+	}
+	for uint64(len(rep.Frames)) < total {
+		run, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("read frame %d: %w", len(rep.Frames), err)
+		}
+		if run == 0 || uint64(len(rep.Frames))+run > total {
+			return nil, fmt.Errorf("corrupt run length %d at frame %d", run, len(rep.Frames))
+		}
+		var fm [2]byte
+		if _, err := io.ReadFull(br, fm[:]); err != nil {
+			return nil, fmt.Errorf("read frame %d: %w", len(rep.Frames), err)
+		}
+		a := decodeFrame(fm[0], int8(fm[1]))
+		for i := uint64(0); i < run; i++ {
+			rep.Frames = append(rep.Frames, a)
+		}
+	}
+
+	return rep, nil
+}
+
+// Save writes the replay to path
+func Save(path string, r *Replay) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Encode(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads a replay from path
+func Load(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rep, err := Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("replay %s: %w", path, err)
+	}
+	return rep, nil
+}
@@ -0,0 +1,130 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+
+	"BRIX/input"
+	"BRIX/levels"
+)
+
+// testHeader is a header with every field set
+var testHeader = Header{
+	Version:        Version,
+	PackID:         "my-pack",
+	StartLevel:     3,
+	Seed:           -42,
+	BrickTypesHash: "bricks",
+	ScoringHash:    "scoring",
+	LevelHash:      "level",
+}
+
+func TestEncodeDecode(t *testing.T) {
+	rep := &Replay{Header: testHeader, Frames: []input.Actions{
+		{Launch: true},
+		{Move: 1},
+		{Move: 1},
+		{Move: -0.5},
+		{Pause: true, Confirm: true},
+		{Back: true, Move: 0.25},
+		{},
+	}}
+	for i, a := range rep.Frames {
+		rep.Frames[i] = Quantize(a)
+	}
+
+	var buf bytes.Buffer
+	if err := rep.Encode(&buf); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, rep) {
+		t.Errorf("round trip = %+v, want %+v", got, rep)
+	}
+}
+
+func TestDecodeVersion1(t *testing.T) {
+	// Version 1 has no pack id; runs are (run length, flags, move)
+	var buf bytes.Buffer
+	var n [binary.MaxVarintLen64]byte
+	putUvarint := func(v uint64) { buf.Write(n[:binary.PutUvarint(n[:], v)]) }
+	putString := func(s string) { putUvarint(uint64(len(s))); buf.WriteString(s) }
+
+	buf.Write(magic[:])
+	buf.WriteByte(1)
+	putUvarint(2)
+	buf.Write(n[:binary.PutVarint(n[:], 7)])
+	putString("bricks")
+	putString("scoring")
+	putString("level")
+	putUvarint(3)
+	putUvarint(2)
+	buf.Write([]byte{flagLaunch, 127})
+	putUvarint(1)
+	buf.Write([]byte{0, byte(0x81)}) // -127
+
+	got, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := &Replay{
+		Header: Header{Version: 1, PackID: levels.BuiltinPackID, StartLevel: 2, Seed: 7,
+			BrickTypesHash: "bricks", ScoringHash: "scoring", LevelHash: "level"},
+		Frames: []input.Actions{{Launch: true, Move: 1}, {Launch: true, Move: 1}, {Move: -1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode = %+v, want %+v", got, want)
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	tests := []struct {
+		move, want float64
+	}{
+		{0, 0},
+		{1, 1},
+		{-1, -1},
+		{2, 1},
+		{-3, -1},
+		{0.5, 64.0 / 127},
+		{0.3, 38.0 / 127},
+	}
+	for _, tt := range tests {
+		got := Quantize(input.Actions{Move: tt.move, Launch: true})
+		if got.Move != tt.want || !got.Launch {
+			t.Errorf("Quantize(%v) = %+v, want move %v", tt.move, got, tt.want)
+		}
+		// A quantized value survives another round unchanged
+		if again := Quantize(got); again != got {
+			t.Errorf("Quantize(Quantize(%v)) = %v, want %v", tt.move, again.Move, got.Move)
+		}
+	}
+}
+
+func TestDecodeBadHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "read magic"},
+		{"wrong magic", []byte("RIFF\x02"), "not a BRIX replay file"},
+		{"version 0", append(magic[:], 0), "unsupported replay version 0"},
+		{"future version", append(magic[:], Version+1), "unsupported replay version"},
+		{"truncated", append(magic[:], Version), "read pack id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Decode error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
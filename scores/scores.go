@@ -0,0 +1,121 @@
+// Package scores keeps the persistent high-score table: the best runs of every
+// level pack, stored as versioned JSON that is replaced atomically on save so a
+// crash mid-write never leaves a truncated file.
+package scores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"BRIX/atomicfile"
+)
+
+const (
+	// FileVersion is the version written to the scores file
+	FileVersion = 1
+
+	// MaxEntries is how many runs are kept per level pack
+	MaxEntries = 10
+
+	// MaxNameLength limits the name entered for a high score
+	MaxNameLength = 12
+)
+
+// Entry is one run in the high-score table
+type Entry struct {
+	Name  string    `json:"name"`
+	Score int       `json:"score"`
+	Level int       `json:"level"` // level reached
+	Lives int       `json:"lives"` // lives remaining when the run ended
+	Date  time.Time `json:"date"`
+}
+
+// Table holds the top entries of every level pack, keyed by pack ID
+type Table struct {
+	Version int                `json:"version"`
+	Packs   map[string][]Entry `json:"packs"`
+}
+
+// New returns an empty table
+func New() *Table {
+	return &Table{Version: FileVersion, Packs: make(map[string][]Entry)}
+}
+
+// DefaultPath returns the scores file in the user's config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "BRIX", "scores.json"), nil
+}
+
+// Load reads a scores file. A missing file is an empty table; a file written by
+// a newer version of the game is an error so it isn't overwritten.
+func Load(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse scores file %s: %v", path, err)
+	}
+	if t.Version < 1 || t.Version > FileVersion {
+		return nil, fmt.Errorf("scores file %s has unsupported version %d (want 1-%d)", path, t.Version, FileVersion)
+	}
+	if t.Packs == nil {
+		t.Packs = make(map[string][]Entry)
+	}
+	t.Version = FileVersion
+	return &t, nil
+}
+
+// Save writes the table to a temporary file beside path and renames it into
+// place, so readers only ever see the old or the new file.
+func Save(path string, t *Table) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scores: %v", err)
+	}
+	return atomicfile.Write(path, append(data, '\n'), 0o644)
+}
+
+// Top returns a pack's entries, best first
+func (t *Table) Top(pack string) []Entry {
+	return t.Packs[pack]
+}
+
+// Qualifies reports whether a score would earn a place in a pack's table
+func (t *Table) Qualifies(pack string, score int) bool {
+	entries := t.Packs[pack]
+	return score > 0 && (len(entries) < MaxEntries || score > entries[len(entries)-1].Score)
+}
+
+// Add inserts an entry into a pack's table and returns its position (from 0),
+// or -1 if it didn't make the table. Equal scores keep the earlier run first.
+func (t *Table) Add(pack string, e Entry) int {
+	if !t.Qualifies(pack, e.Score) {
+		return -1
+	}
+	entries := t.Packs[pack]
+	rank := sort.Search(len(entries), func(i int) bool { return entries[i].Score < e.Score })
+
+	entries = append(entries, Entry{})
+	copy(entries[rank+1:], entries[rank:])
+	entries[rank] = e
+	if len(entries) > MaxEntries {
+		entries = entries[:MaxEntries]
+	}
+	t.Packs[pack] = entries
+	return rank
+}
@@ -0,0 +1,103 @@
+// Package settings keeps the player's preferences, such as sound volume, in a
+// JSON file in the user's config directory. Settings are not gameplay config:
+// they never affect the simulation, so replays ignore them.
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"BRIX/atomicfile"
+)
+
+// Settings are the player's preferences
+type Settings struct {
+	Volume      float64 `json:"volume"`       // master volume, 0-1
+	SFXVolume   float64 `json:"sfx_volume"`   // sound effect volume relative to the master volume, 0-1
+	MusicVolume float64 `json:"music_volume"` // music volume relative to the master volume, 0-1
+	Muted       bool    `json:"muted"`
+
+	// Display
+	Effects bool   `json:"effects"`           // particles, trails and score popups; off for slow machines
+	Sprites string `json:"sprites,omitempty"` // sprite pack directory or zip used instead of the default sprites
+}
+
+// Default returns the settings used when the player hasn't changed anything
+func Default() Settings {
+	return Settings{Volume: 0.8, SFXVolume: 1, MusicVolume: 0.6, Effects: true}
+}
+
+// DefaultPath returns the settings file in the user's config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "BRIX", "settings.json"), nil
+}
+
+// Load reads a settings file over the defaults, so keys missing from the file
+// keep their default values. A missing file is the defaults; an unreadable one
+// returns the defaults with an error.
+func Load(path string) (Settings, error) {
+	s := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Default(), fmt.Errorf("failed to parse settings file %s: %v", path, err)
+	}
+	s.clamp()
+	return s, nil
+}
+
+// Save writes the settings file atomically
+func Save(path string, s Settings) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %v", err)
+	}
+	return atomicfile.Write(path, append(data, '\n'), 0o644)
+}
+
+// EffectiveSFXVolume returns the volume sound effects play at, 0 when muted
+func (s Settings) EffectiveSFXVolume() float64 {
+	if s.Muted {
+		return 0
+	}
+	return s.Volume * s.SFXVolume
+}
+
+// EffectiveMusicVolume returns the volume music plays at, 0 when muted
+func (s Settings) EffectiveMusicVolume() float64 {
+	if s.Muted {
+		return 0
+	}
+	return s.Volume * s.MusicVolume
+}
+
+// AdjustVolume changes the master volume by delta, keeping it within 0-1 and
+// rounded to a hundredth so repeated steps land on round numbers
+func (s *Settings) AdjustVolume(delta float64) {
+	s.Volume = math.Round((s.Volume+delta)*100) / 100
+	s.clamp()
+}
+
+// clamp keeps the volumes within 0-1
+func (s *Settings) clamp() {
+	s.Volume = clamp01(s.Volume)
+	s.SFXVolume = clamp01(s.SFXVolume)
+	s.MusicVolume = clamp01(s.MusicVolume)
+}
+
+func clamp01(v float64) float64 {
+	return min(max(v, 0), 1)
+}
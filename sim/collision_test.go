@@ -0,0 +1,70 @@
+package sim
+
+import (
+	"math"
+	"testing"
+
+	"BRIX/entities"
+	"BRIX/input"
+)
+
+// TestPaddleSqueezesBallAgainstWall drives the paddle into a ball lying against
+// the right wall. The ball must stay inside the walls and fall past the paddle
+// rather than being trapped between the two.
+func TestPaddleSqueezesBallAgainstWall(t *testing.T) {
+	w := newTestWorld(t, cornerBrick)
+	w.Step(input.Actions{Launch: true})
+	placeBalls(w, testBall{x: 720, y: 500, vx: 0, vy: -100})
+
+	// Drive the paddle until it is nearly at the wall, then put the ball in its way
+	right := input.Actions{Move: 1}
+	for _, _, r, _ := w.Paddle().GetBounds(); r < entities.GameAreaRight-20; _, _, r, _ = w.Paddle().GetBounds() {
+		w.Step(right)
+	}
+	limit := entities.GameAreaRight - entities.BallRadius
+	placeBalls(w, testBall{x: limit - 1, y: entities.PaddleY + 20, vx: 300, vy: 50})
+
+	for i := 0; i < 120 && w.State() == StatePlaying; i++ {
+		w.Step(right)
+		if n := len(w.Events()); n >= maxContactsForTest {
+			t.Fatalf("tick %d: %d contacts, the ball is stuck", i, n)
+		}
+		for _, b := range w.Balls() {
+			if b.X() > limit+1e-9 {
+				t.Fatalf("tick %d: ball pushed through the wall to x=%.1f (limit %.1f)", i, b.X(), limit)
+			}
+		}
+	}
+	if w.State() != StateWaitingToContinue {
+		t.Errorf("state = %d, want the ball lost past the paddle (%d)", w.State(), StateWaitingToContinue)
+	}
+}
+
+// maxContactsForTest is more contacts in one tick than a free ball ever makes
+const maxContactsForTest = 8
+
+// TestBallStartingInCornerSquareHitsCorner starts a ball near a brick's corner,
+// inside the box grown by its radius but not yet touching the rounded corner,
+// moving diagonally onto it. It must bounce off the corner in that tick rather
+// than sliding into the brick.
+func TestBallStartingInCornerSquareHitsCorner(t *testing.T) {
+	w := newTestWorld(t, entities.LevelBrick{PixelX: 600, PixelY: 200, Type: "standard", Hits: 2})
+	w.Step(input.Actions{Launch: true})
+
+	left, top, _, _ := w.Bricks()[0].GetBounds()
+	r := float64(entities.BallRadius)
+	speed := 15 / entities.Tick // 15px a tick along each axis
+	placeBalls(w, testBall{x: left - 0.9*r, y: top - 0.9*r, vx: speed, vy: speed})
+
+	w.Step(input.Actions{})
+	if len(w.Events()) == 0 {
+		t.Fatalf("no contact in the first tick")
+	}
+	b := w.Balls()[0]
+	if vx, vy := b.VX(), b.VY(); vx >= 0 || vy >= 0 {
+		t.Errorf("velocity after the corner = (%.1f, %.1f), want it reflected back up and left", vx, vy)
+	}
+	if b.X() > left-r/math.Sqrt2 || b.Y() > top-r/math.Sqrt2 {
+		t.Errorf("ball at (%.1f, %.1f) overlaps the brick's corner at (%.1f, %.1f)", b.X(), b.Y(), left, top)
+	}
+}
@@ -0,0 +1,129 @@
+package sim
+
+import (
+	"fmt"
+	"log"
+
+	"BRIX/entities"
+	"BRIX/input"
+)
+
+// EndOption is a choice offered when a run ends
+type EndOption int
+
+const (
+	EndContinue EndOption = iota // replay the current level with full lives, for a score penalty
+	EndRestart                   // start the pack again from level 1
+	EndMenu                      // go back to the start screen
+)
+
+// ContinuePenalty is the number of points taken for continuing after game over
+const ContinuePenalty = 2000
+
+// endInputDelay is how long the end-of-run screens ignore input (half a second),
+// so a button mashed as the last ball drops doesn't pick an option by accident
+const endInputDelay = 30
+
+// String returns the option's menu label
+func (o EndOption) String() string {
+	switch o {
+	case EndContinue:
+		return fmt.Sprintf("Continue this level (-%d points)", ContinuePenalty)
+	case EndRestart:
+		return "Restart from level 1"
+	case EndMenu:
+		return "Return to start screen"
+	}
+	return "unknown"
+}
+
+// Finished reports whether the run has ended, by game over or by clearing every level
+func (w *World) Finished() bool {
+	return w.state == StateGameOver || w.state == StateCampaignComplete
+}
+
+// Run counts the runs started in this world; it changes whenever an end-of-run
+// option resets the world, so callers can tell a new run from the old one
+func (w *World) Run() int {
+	return w.run
+}
+
+// EndOptions returns the choices on the current end-of-run screen, or nil if the run hasn't ended
+func (w *World) EndOptions() []EndOption {
+	switch w.state {
+	case StateGameOver:
+		return []EndOption{EndContinue, EndRestart, EndMenu}
+	case StateCampaignComplete:
+		return []EndOption{EndRestart, EndMenu}
+	}
+	return nil
+}
+
+// EndChoice returns the index of the highlighted end-of-run option
+func (w *World) EndChoice() int {
+	return w.endChoice
+}
+
+// finish ends the run in a game-over or campaign-complete state
+func (w *World) finish(state GameState) {
+	w.state = state
+	w.endChoice = 0
+	w.endTicks = 0
+}
+
+// updateEnd moves through the end-of-run options with the paddle controls and applies one on confirm
+func (w *World) updateEnd(in input.Actions) {
+	if w.endTicks < endInputDelay {
+		w.endTicks++
+		return
+	}
+
+	options := w.EndOptions()
+	if in.Back {
+		w.choose(EndMenu)
+		return
+	}
+	if in.Move != 0 && w.lastMove == 0 {
+		step := 1
+		if in.Move < 0 {
+			step = -1
+		}
+		w.endChoice = (w.endChoice + step + len(options)) % len(options)
+	}
+	if in.Confirm {
+		w.choose(options[w.endChoice])
+	}
+}
+
+// choose applies an end-of-run option
+func (w *World) choose(o EndOption) {
+	switch o {
+	case EndContinue:
+		score := max(w.score-ContinuePenalty, 0)
+		w.startRun(w.currentLevel, StatePlaying)
+		w.score = score
+		log.Printf("Continuing level %d with %d points", w.currentLevel, w.score)
+	case EndRestart:
+		w.startRun(1, StatePlaying)
+		log.Printf("Restarted from level 1")
+	case EndMenu:
+		w.startRun(1, StateStart)
+	}
+}
+
+// startRun resets the world to the beginning of a level with full lives, no
+// score and a fresh paddle and ball.
+func (w *World) startRun(levelNum int, state GameState) {
+	w.clearPowerUps()
+	w.run++
+	w.score = 0
+	w.lives = StartingLives
+	w.currentLevel = levelNum
+	w.paddle = entities.NewPaddle()
+	if err := w.loadLevel(levelNum); err != nil {
+		log.Printf("Failed to load level %d: %v", levelNum, err)
+		w.createFallbackLevel()
+	}
+	w.balls = []*entities.Ball{w.newBall()}
+	w.state = state
+}
@@ -0,0 +1,55 @@
+package sim
+
+import (
+	"BRIX/entities"
+	"BRIX/physics"
+)
+
+// EventKind identifies something that happened during a Step
+type EventKind int
+
+const (
+	EventPaddleHit EventKind = iota
+	EventWallBounce
+	EventBrickHit       // a brick was hit but survived
+	EventBrickDestroyed // a brick took its last hit
+	EventLifeLost
+	EventLevelComplete
+)
+
+// Event is something that happened during a Step, for sound and effects. The
+// simulation never reads events back, so listeners can't affect gameplay.
+type Event struct {
+	Kind   EventKind
+	X, Y   float64         // where it happened, for contacts
+	Brick  *entities.Brick // the brick involved, for brick events
+	Points int             // points awarded
+}
+
+// Events returns what happened during the last Step. The slice is reused by the next Step.
+func (w *World) Events() []Event {
+	return w.events
+}
+
+// emit records an event for the current Step
+func (w *World) emit(e Event) {
+	w.events = append(w.events, e)
+}
+
+// emitContacts records a ball's collisions as events
+func (w *World) emitContacts(contacts []physics.Contact) {
+	for _, c := range contacts {
+		e := Event{X: c.X, Y: c.Y, Brick: c.Brick, Points: c.Points}
+		switch {
+		case c.Kind == physics.ContactWall:
+			e.Kind = EventWallBounce
+		case c.Kind == physics.ContactPaddle:
+			e.Kind = EventPaddleHit
+		case c.Destroyed:
+			e.Kind = EventBrickDestroyed
+		default:
+			e.Kind = EventBrickHit
+		}
+		w.emit(e)
+	}
+}
@@ -0,0 +1,57 @@
+package sim
+
+import (
+	"strconv"
+
+	"BRIX/config"
+	"BRIX/entities"
+	"BRIX/powerups"
+)
+
+// dropPowerUp releases a capsule if the destroyed brick's type carries a registered power-up
+func (w *World) dropPowerUp(brick *entities.Brick) {
+	cfg, ok := config.Brick[string(brick.Type())]
+	if !ok {
+		return
+	}
+	if _, ok := powerups.Lookup(cfg.PowerUp); !ok {
+		return // "none" or an effect this build doesn't know about
+	}
+
+	left, top, right, bottom := brick.GetBounds()
+	w.capsules = append(w.capsules, entities.NewCapsule((left+right)/2, (top+bottom)/2, cfg.PowerUp))
+}
+
+// updateCapsules moves falling capsules and applies the ones the paddle catches
+func (w *World) updateCapsules() {
+	kept := w.capsules[:0]
+	for _, c := range w.capsules {
+		c.Update()
+		if w.physics.CheckCapsuleCollision(c, w.paddle) {
+			c.Deactivate()
+			w.catchPowerUp(c.Kind())
+			continue
+		}
+		if c.IsLost() {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	w.capsules = kept
+}
+
+// catchPowerUp activates the named effect and awards its per-lives points
+func (w *World) catchPowerUp(name string) {
+	effect, ok := powerups.Lookup(name)
+	if !ok {
+		return
+	}
+	w.effects.Activate(effect, w)
+	w.score += config.Score.PowerUp[name][strconv.Itoa(w.lives)]
+}
+
+// clearPowerUps reverts all active effects and removes falling capsules
+func (w *World) clearPowerUps() {
+	w.effects.Clear(w)
+	w.capsules = nil
+}
@@ -0,0 +1,412 @@
+// Package sim contains the headless gameplay simulation. It has no dependency on
+// Ebitengine, so the whole game loop can be driven from tests, tools or CI.
+package sim
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"BRIX/entities"
+	"BRIX/input"
+	"BRIX/levels"
+	"BRIX/physics"
+	"BRIX/powerups"
+)
+
+// GameState represents the current state of the game
+type GameState int
+
+const (
+	StateStart GameState = iota
+	StatePlaying
+	StatePaused
+	StateLevelComplete
+	StateWaitingToContinue
+	StateGameOver
+	StateCampaignComplete // every level of the pack was cleared
+)
+
+// World holds the complete gameplay state and advances it one entities.Tick at a time.
+type World struct {
+	paddle   *entities.Paddle
+	balls    []*entities.Ball
+	bricks   []*entities.Brick
+	capsules []*entities.Capsule
+	pack     *levels.Pack
+	level    *levels.Level
+
+	currentLevel int
+	score        int
+	lives        int // player lives
+	state        GameState
+	run          int // number of runs started, see Run
+
+	endChoice int     // highlighted end-of-run option
+	endTicks  int     // ticks spent on the end-of-run screen, to ignore input at first
+	lastMove  float64 // previous tick's move input, to step the end-of-run menu once per press
+
+	events []Event // what happened during the last Step
+
+	physics *physics.CollisionSystem
+	effects *powerups.Manager
+
+	seed int64 // recorded in replays and saves; gameplay has no randomness yet, so it changes nothing
+}
+
+// Options configures a new World
+type Options struct {
+	Pack       *levels.Pack // level pack to play; defaults to levels.BuiltinPack
+	StartLevel int          // first level to play; defaults to 1
+	Seed       int64        // identifies the run in replays and saves; reserved for future gameplay randomness
+
+	Level        *levels.Level // play this in-memory level as StartLevel instead of loading it
+	StartPlaying bool          // skip the start screen
+}
+
+// StartingLives is the number of lives a new game begins with
+const StartingLives = 3
+
+// New creates a world positioned on the start screen of the configured level
+func New(opts Options) *World {
+	if opts.StartLevel <= 0 {
+		opts.StartLevel = 1
+	}
+
+	w := &World{
+		currentLevel: opts.StartLevel,
+		score:        0,
+		lives:        StartingLives,
+		state:        StateStart,
+		physics:      physics.NewCollisionSystem(),
+		effects:      powerups.NewManager(),
+		seed:         opts.Seed,
+	}
+
+	w.pack = opts.Pack
+	if w.pack == nil {
+		pack, err := levels.BuiltinPack()
+		if err != nil {
+			log.Printf("Failed to open builtin levels: %v", err)
+		}
+		w.pack = pack
+	}
+
+	// Initialize game entities
+	w.paddle = entities.NewPaddle()
+
+	// Load the first level
+	if opts.Level != nil {
+		w.useLevel(opts.StartLevel, opts.Level)
+	} else if err := w.loadLevel(opts.StartLevel); err != nil {
+		log.Printf("Failed to load level %d: %v", opts.StartLevel, err)
+		w.createFallbackLevel()
+	}
+
+	if opts.StartPlaying {
+		w.state = StatePlaying
+	}
+
+	// Create ball with level's speed positioned above paddle
+	w.balls = []*entities.Ball{w.newBall()}
+
+	return w
+}
+
+// Paddle returns the player's paddle
+func (w *World) Paddle() *entities.Paddle {
+	return w.paddle
+}
+
+// Balls returns the balls in play
+func (w *World) Balls() []*entities.Ball {
+	return w.balls
+}
+
+// AddBall puts an extra ball into play
+func (w *World) AddBall(ball *entities.Ball) {
+	w.balls = append(w.balls, ball)
+}
+
+// Bricks returns all bricks of the current level, including destroyed ones
+func (w *World) Bricks() []*entities.Brick {
+	return w.bricks
+}
+
+// Capsules returns the power-up capsules currently falling
+func (w *World) Capsules() []*entities.Capsule {
+	return w.capsules
+}
+
+// ActiveEffects returns the power-up effects currently applied
+func (w *World) ActiveEffects() []powerups.Status {
+	return w.effects.Active()
+}
+
+// Pack returns the level pack being played, or nil if none could be opened
+func (w *World) Pack() *levels.Pack {
+	return w.pack
+}
+
+// Level returns the current level definition
+func (w *World) Level() *levels.Level {
+	return w.level
+}
+
+// CurrentLevel returns the current level number
+func (w *World) CurrentLevel() int {
+	return w.currentLevel
+}
+
+// Score returns the player's score
+func (w *World) Score() int {
+	return w.score
+}
+
+// Lives returns the player's remaining lives
+func (w *World) Lives() int {
+	return w.lives
+}
+
+// Seed returns the seed the world was created with
+func (w *World) Seed() int64 {
+	return w.seed
+}
+
+// State returns the current game state
+func (w *World) State() GameState {
+	return w.state
+}
+
+// Step advances the simulation by one entities.Tick using the given actions
+func (w *World) Step(in input.Actions) {
+	w.events = w.events[:0]
+	switch w.state {
+	case StateStart:
+		w.updateStart(in)
+	case StatePlaying:
+		w.updatePlaying(in)
+	case StatePaused:
+		w.updatePaused(in)
+	case StateLevelComplete:
+		w.updateLevelComplete(in)
+	case StateWaitingToContinue:
+		w.updateWaitingToContinue(in)
+	case StateGameOver, StateCampaignComplete:
+		w.updateEnd(in)
+	}
+	w.lastMove = in.Move
+}
+
+// loadLevel loads a level from the levels package
+func (w *World) loadLevel(levelNum int) error {
+	if w.pack == nil {
+		return fmt.Errorf("no level pack to load level %d from", levelNum)
+	}
+	level, err := w.pack.LoadLevel(levelNum)
+	if err != nil {
+		return err
+	}
+	w.useLevel(levelNum, level)
+	return nil
+}
+
+// useLevel replaces the bricks with those of an already loaded level
+func (w *World) useLevel(levelNum int, level *levels.Level) {
+	// Guarantee score baseline: at least 1000 points per level number.
+	baseline := levelNum * 1000
+	if w.score < baseline {
+		w.score = baseline
+	}
+
+	w.level = level
+	w.bricks = levels.BuildBricks(level)
+
+	log.Printf("Level loaded: %s with %d bricks (format: %s)", level.Name, len(w.bricks),
+		map[bool]string{true: "pixel-perfect", false: "grid-based"}[level.UsePixelPositioning])
+}
+
+// ReloadLevel reloads the current level from pack for live editing, keeping the
+// paddle, balls, score and lives. The bricks are rebuilt as in the file and the
+// balls take the level's new speed settings. On error the world is unchanged.
+func (w *World) ReloadLevel(pack *levels.Pack) error {
+	level, err := pack.LoadLevel(w.currentLevel)
+	if err != nil {
+		return err
+	}
+
+	previous := w.level
+	w.pack = pack
+	w.level = level
+	w.bricks = levels.BuildBricks(level)
+	for _, ball := range w.balls {
+		ball.SetSpeedLimits(level.SpeedLimits())
+		if previous == nil || level.BallSpeed != previous.BallSpeed {
+			ball.SetSpeed(math.Hypot(level.BallSpeed, level.BallSpeed))
+		}
+	}
+
+	log.Printf("Level reloaded: %s with %d bricks", level.Name, len(w.bricks))
+	return nil
+}
+
+// newBall creates a ball above the paddle with the current level's speed and limits
+func (w *World) newBall() *entities.Ball {
+	ball := entities.NewBallAbovePaddle(w.paddle.X(), w.level.BallSpeed)
+	ball.SetSpeedLimits(w.level.SpeedLimits())
+	return ball
+}
+
+// calculateBrickFieldBounds calculates the minimum and maximum X coordinates used in the level
+func (w *World) calculateBrickFieldBounds(level *levels.Level) (int, int) {
+	if len(level.Bricks) == 0 {
+		return 0, 0
+	}
+
+	minX := level.Bricks[0].X
+	maxX := level.Bricks[0].X
+
+	for _, brick := range level.Bricks {
+		if brick.X < minX {
+			minX = brick.X
+		}
+		if brick.X > maxX {
+			maxX = brick.X
+		}
+	}
+
+	return minX, maxX
+}
+
+// createFallbackLevel creates a simple level if loading fails
+func (w *World) createFallbackLevel() {
+	w.level = &levels.Level{
+		Name:          "Default Level",
+		BrickWidth:    150,
+		BrickHeight:   60,
+		BrickSpacingX: 40,
+		BrickSpacingY: 30,
+		BallSpeed:     400,
+	}
+
+	// Create a simple pattern of bricks with fallback sizing
+	w.bricks = []*entities.Brick{
+		entities.NewBrickFromLevelWithBounds(entities.LevelBrick{X: 2, Y: 2, BrickType: "standard", Hits: 1}, 150, 60, 40, 30, 2, 5),
+		entities.NewBrickFromLevelWithBounds(entities.LevelBrick{X: 3, Y: 2, BrickType: "standard", Hits: 1}, 150, 60, 40, 30, 2, 5),
+		entities.NewBrickFromLevelWithBounds(entities.LevelBrick{X: 4, Y: 2, BrickType: "standard", Hits: 1}, 150, 60, 40, 30, 2, 5),
+		entities.NewBrickFromLevelWithBounds(entities.LevelBrick{X: 5, Y: 2, BrickType: "standard", Hits: 1}, 150, 60, 40, 30, 2, 5),
+	}
+}
+
+// updateStart handles start screen input
+func (w *World) updateStart(in input.Actions) {
+	if in.Launch {
+		w.state = StatePlaying
+	}
+}
+
+// updatePlaying handles main game logic
+func (w *World) updatePlaying(in input.Actions) {
+	if in.Pause {
+		w.state = StatePaused
+		return
+	}
+
+	// Update paddle
+	w.paddle.Update(in.Move)
+
+	// Move each ball independently, resolving every collision along its path this tick
+	var destroyed []*entities.Brick
+	for _, ball := range w.balls {
+		contacts := w.physics.MoveBall(ball, w.paddle, w.bricks, &w.score, w.lives)
+		for _, c := range contacts {
+			if c.Destroyed {
+				destroyed = append(destroyed, c.Brick)
+			}
+		}
+		w.emitContacts(contacts)
+	}
+
+	// Release power-ups from destroyed bricks, then advance falling capsules and timed effects
+	for _, brick := range destroyed {
+		w.dropPowerUp(brick)
+	}
+	w.updateCapsules()
+	w.effects.Update(entities.Tick, w)
+
+	// Drop balls that left the field; a life is only lost with the last one
+	kept := w.balls[:0]
+	for _, ball := range w.balls {
+		if !ball.IsLost() {
+			kept = append(kept, ball)
+		}
+	}
+	w.balls = kept
+
+	if len(w.balls) == 0 {
+		w.clearPowerUps()
+		w.lives-- // Subtract life immediately when ball is lost
+		w.emit(Event{Kind: EventLifeLost})
+		if w.lives <= 0 {
+			w.finish(StateGameOver)
+			return // losing the last life outranks clearing the level in the same tick
+		}
+		w.state = StateWaitingToContinue
+	}
+
+	// Check if level is complete; unbreakable bricks don't need clearing
+	activeBricks := 0
+	for _, brick := range w.bricks {
+		if brick.IsActive() && brick.Breakable() {
+			activeBricks++
+		}
+	}
+
+	if activeBricks == 0 {
+		// Level complete - could advance to next level here
+		w.state = StateLevelComplete
+		w.emit(Event{Kind: EventLevelComplete})
+	}
+}
+
+// updateWaitingToContinue handles waiting to continue after losing a life
+func (w *World) updateWaitingToContinue(in input.Actions) {
+	if in.Any() {
+		// Reset ball position and continue playing (life already decremented)
+		w.balls = []*entities.Ball{w.newBall()}
+		w.state = StatePlaying
+	}
+}
+
+// updatePaused handles pause screen input
+func (w *World) updatePaused(in input.Actions) {
+	if in.Any() {
+		w.state = StatePlaying
+	}
+}
+
+// updateLevelComplete handles level complete state
+func (w *World) updateLevelComplete(in input.Actions) {
+	if !in.Any() {
+		return
+	}
+
+	w.clearPowerUps()
+
+	// Try to advance to the next level
+	nextLevel := w.currentLevel + 1
+	if w.pack == nil || nextLevel > w.pack.Len() {
+		// No more levels - game complete!
+		log.Printf("No level %d in pack, game complete!", nextLevel)
+		w.finish(StateCampaignComplete)
+	} else if err := w.loadLevel(nextLevel); err != nil {
+		log.Printf("Failed to load level %d: %v", nextLevel, err)
+		w.finish(StateGameOver)
+	} else {
+		// Successfully loaded next level
+		w.currentLevel = nextLevel
+		w.balls = []*entities.Ball{w.newBall()}
+		w.state = StatePlaying
+		log.Printf("Advanced to level %d", nextLevel)
+	}
+}
@@ -0,0 +1,143 @@
+package sim
+
+import (
+	"testing"
+
+	"BRIX/entities"
+	"BRIX/input"
+	"BRIX/levels"
+)
+
+// testBall places a ball for a test: position and velocity in px and px/s
+type testBall struct {
+	x, y, vx, vy float64
+}
+
+// newTestWorld creates a world on the start screen of an in-memory level with the given bricks
+func newTestWorld(t *testing.T, bricks ...entities.LevelBrick) *World {
+	t.Helper()
+	level := &levels.Level{
+		Name:                "Test",
+		UsePixelPositioning: true,
+		DefaultBrickWidth:   100,
+		DefaultBrickHeight:  40,
+		BallSpeed:           400,
+		Bricks:              bricks,
+	}
+	return New(Options{Level: level, Seed: 1})
+}
+
+// placeBalls replaces the balls in play
+func placeBalls(w *World, balls ...testBall) {
+	w.balls = nil
+	for _, b := range balls {
+		ball := w.newBall()
+		ball.SetPosition(b.x, b.y)
+		ball.SetVelocity(b.vx, b.vy)
+		w.balls = append(w.balls, ball)
+	}
+}
+
+// cornerBrick is out of the way of every ball the tests place
+var cornerBrick = entities.LevelBrick{PixelX: 0, PixelY: 0, Type: "standard", Hits: 1}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name   string
+		bricks []entities.LevelBrick
+		lives  int
+		balls  []testBall
+
+		wantState GameState
+		wantLives int
+		wantBalls int
+	}{
+		{
+			name:      "launch",
+			bricks:    []entities.LevelBrick{cornerBrick},
+			lives:     StartingLives,
+			balls:     []testBall{{x: 720, y: 500, vx: 0, vy: -300}},
+			wantState: StatePlaying,
+			wantLives: StartingLives,
+			wantBalls: 1,
+		},
+		{
+			name:      "life lost",
+			bricks:    []entities.LevelBrick{cornerBrick},
+			lives:     StartingLives,
+			balls:     []testBall{{x: 100, y: 1040, vx: 0, vy: 600}},
+			wantState: StateWaitingToContinue,
+			wantLives: StartingLives - 1,
+			wantBalls: 0,
+		},
+		{
+			name:      "game over",
+			bricks:    []entities.LevelBrick{cornerBrick},
+			lives:     1,
+			balls:     []testBall{{x: 100, y: 1040, vx: 0, vy: 600}},
+			wantState: StateGameOver,
+			wantLives: 0,
+			wantBalls: 0,
+		},
+		{
+			name:      "level complete",
+			bricks:    []entities.LevelBrick{{PixelX: 600, PixelY: 200, Type: "standard", Hits: 1}},
+			lives:     StartingLives,
+			balls:     []testBall{{x: 670, y: 330, vx: 0, vy: -600}},
+			wantState: StateLevelComplete,
+			wantLives: StartingLives,
+			wantBalls: 1,
+		},
+		{
+			// The ball glances off the side of a brick below the field on its way out
+			name:      "last life lost as the last brick breaks",
+			bricks:    []entities.LevelBrick{{PixelX: 600, PixelY: entities.GameAreaHeight + 80, Type: "standard", Hits: 1}},
+			lives:     1,
+			balls:     []testBall{{x: entities.GameAreaLeft + 600 - entities.BallRadius - 2, y: entities.GameAreaBottom + 95, vx: 600, vy: 600}},
+			wantState: StateGameOver,
+			wantLives: 0,
+			wantBalls: 0,
+		},
+		{
+			name:   "one of several balls lost",
+			bricks: []entities.LevelBrick{cornerBrick},
+			lives:  StartingLives,
+			balls: []testBall{
+				{x: 100, y: 1040, vx: 0, vy: 600},
+				{x: 720, y: 500, vx: 0, vy: -300},
+			},
+			wantState: StatePlaying,
+			wantLives: StartingLives,
+			wantBalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTestWorld(t, tt.bricks...)
+			if w.State() != StateStart {
+				t.Fatalf("new world is in state %d, want the start screen", w.State())
+			}
+			w.Step(input.Actions{Launch: true})
+			if w.State() != StatePlaying {
+				t.Fatalf("launching left the world in state %d", w.State())
+			}
+
+			w.lives = tt.lives
+			placeBalls(w, tt.balls...)
+			for i := 0; i < 30 && w.State() == StatePlaying; i++ {
+				w.Step(input.Actions{})
+			}
+
+			if w.State() != tt.wantState {
+				t.Errorf("state = %d, want %d", w.State(), tt.wantState)
+			}
+			if w.Lives() != tt.wantLives {
+				t.Errorf("lives = %d, want %d", w.Lives(), tt.wantLives)
+			}
+			if len(w.Balls()) != tt.wantBalls {
+				t.Errorf("%d balls in play, want %d", len(w.Balls()), tt.wantBalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"BRIX/atomicfile"
+	"BRIX/entities"
+	"BRIX/levels"
+	"BRIX/physics"
+	"BRIX/powerups"
+)
+
+// SnapshotVersion is the version written to save files
+const SnapshotVersion = 1
+
+// Snapshot is a game in progress, saved so it can be resumed later. Bricks are
+// rebuilt from the level file, so only their changing state is stored; the
+// level's hash guards against resuming after the level file was edited.
+type Snapshot struct {
+	Version   int    `json:"version"`
+	PackID    string `json:"pack"`
+	Level     int    `json:"level"`
+	LevelHash string `json:"level_hash"`
+	Score     int    `json:"score"`
+	Lives     int    `json:"lives"`
+	Seed      int64  `json:"seed"`
+
+	Paddle   entities.PaddleState    `json:"paddle"`
+	Balls    []entities.BallState    `json:"balls"`
+	Bricks   []entities.BrickState   `json:"bricks"`
+	Capsules []entities.CapsuleState `json:"capsules,omitempty"`
+	Effects  []powerups.Status       `json:"effects,omitempty"`
+}
+
+// CanSave reports whether the world is in the middle of a run worth saving
+func (w *World) CanSave() bool {
+	switch w.state {
+	case StatePlaying, StatePaused, StateWaitingToContinue:
+		return true
+	}
+	return false
+}
+
+// Snapshot captures the world's current run
+func (w *World) Snapshot() *Snapshot {
+	s := &Snapshot{
+		Version:   SnapshotVersion,
+		Level:     w.currentLevel,
+		LevelHash: levels.Hash(w.level),
+		Score:     w.score,
+		Lives:     w.lives,
+		Seed:      w.seed,
+		Paddle:    w.paddle.State(),
+		Effects:   w.effects.Active(),
+	}
+	if w.pack != nil {
+		s.PackID = w.pack.ID()
+	}
+	for _, b := range w.balls {
+		s.Balls = append(s.Balls, b.State())
+	}
+	for _, b := range w.bricks {
+		s.Bricks = append(s.Bricks, b.State())
+	}
+	for _, c := range w.capsules {
+		if c.IsActive() {
+			s.Capsules = append(s.Capsules, c.State())
+		}
+	}
+	return s
+}
+
+// Restore recreates a saved run from its level pack. The world starts paused.
+func Restore(s *Snapshot, pack *levels.Pack) (*World, error) {
+	if pack == nil {
+		return nil, fmt.Errorf("no level pack to resume level %d from", s.Level)
+	}
+	level, err := pack.LoadLevel(s.Level)
+	if err != nil {
+		return nil, err
+	}
+	if levels.Hash(level) != s.LevelHash {
+		return nil, fmt.Errorf("level %d has changed since the game was saved", s.Level)
+	}
+
+	w := &World{
+		paddle:       entities.NewPaddle(),
+		pack:         pack,
+		level:        level,
+		currentLevel: s.Level,
+		score:        s.Score,
+		lives:        s.Lives,
+		state:        StatePaused,
+		physics:      physics.NewCollisionSystem(),
+		effects:      powerups.NewManager(),
+		seed:         s.Seed,
+	}
+
+	w.bricks = levels.BuildBricks(level)
+	if len(w.bricks) != len(s.Bricks) {
+		return nil, fmt.Errorf("save has %d bricks but level %d has %d", len(s.Bricks), s.Level, len(w.bricks))
+	}
+	for i, b := range w.bricks {
+		b.SetState(s.Bricks[i])
+	}
+
+	w.paddle.SetState(s.Paddle)
+	for _, b := range s.Balls {
+		w.balls = append(w.balls, entities.NewBallFromState(b))
+	}
+	if len(w.balls) == 0 {
+		w.balls = []*entities.Ball{w.newBall()}
+	}
+	for _, c := range s.Capsules {
+		w.capsules = append(w.capsules, entities.NewCapsuleFromState(c))
+	}
+	// Effects are already reflected in the saved paddle and balls; only their timers resume
+	for _, e := range s.Effects {
+		if effect, ok := powerups.Lookup(e.Name); ok {
+			w.effects.Resume(effect, e.Remaining)
+		}
+	}
+	return w, nil
+}
+
+// SaveSnapshot writes a snapshot to path atomically
+func SaveSnapshot(path string, s *Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode saved game: %v", err)
+	}
+	return atomicfile.Write(path, append(data, '\n'), 0o644)
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse saved game %s: %v", path, err)
+	}
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("saved game %s has unsupported version %d", path, s.Version)
+	}
+	return &s, nil
+}
+
+// SnapshotExists reports whether a saved game is waiting at path
+func SnapshotExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
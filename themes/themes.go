@@ -0,0 +1,266 @@
+// Package themes is the registry of level themes. A theme sets the look of a
+// level: background image, tint, brick sprite overrides, paddle skin and music.
+// Builtin themes are embedded; more are read from theme directories, each
+// holding a theme.json and the files it names.
+package themes
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"BRIX/config"
+)
+
+// DefaultID is the theme of levels that don't name one
+const DefaultID = "default"
+
+// ManifestFile describes a theme in a theme directory
+const ManifestFile = "theme.json"
+
+// Theme is one entry of the registry. File names are slash-separated paths
+// relative to the theme: its directory, or the game's assets for builtin themes.
+type Theme struct {
+	Name       string            `json:"name"`
+	Background string            `json:"background,omitempty"` // image stretched over the playfield
+	Tint       string            `json:"tint,omitempty"`       // "#rrggbb" the background is multiplied by
+	Bricks     map[string]string `json:"bricks,omitempty"`     // brick type to sprite file, replacing the type's sprite
+	Paddle     string            `json:"paddle,omitempty"`     // paddle sprite file
+	Music      string            `json:"music,omitempty"`      // music looped when the level has none of its own
+
+	id     string
+	source string // directory, or "" for builtin themes
+	fsys   fs.FS  // nil for builtin themes, which read from builtinFiles
+}
+
+//go:embed builtin/*.json
+var builtinManifests embed.FS
+
+// builtinFiles holds the files of builtin themes; see SetBuiltinFiles
+var builtinFiles fs.FS
+
+// registry holds every known theme by ID
+var registry map[string]*Theme
+
+// init registers the builtin themes so levels can be validated before Load
+func init() {
+	registry = make(map[string]*Theme)
+	if err := addBuiltins(registry); err != nil {
+		panic(fmt.Sprintf("themes: builtin themes are invalid: %v", err))
+	}
+}
+
+// SetBuiltinFiles supplies the files builtin themes refer to. The assets
+// package calls it with its embedded sprites.
+func SetBuiltinFiles(fsys fs.FS) {
+	builtinFiles = fsys
+}
+
+// Dirs returns the directories searched for themes: themes/ next to the
+// working directory and BRIX/themes in the user's config directory.
+func Dirs() []string {
+	dirs := []string{"themes"}
+	if cfg, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(cfg, "BRIX", "themes"))
+	}
+	return dirs
+}
+
+// Load rebuilds the registry from the builtin themes and every theme directory
+// (one holding a theme.json) found directly inside Dirs and extraDirs. A theme
+// may replace one with the same ID from an earlier directory. Themes that fail
+// to load are reported in the returned error and skipped; the rest are still
+// registered.
+func Load(extraDirs ...string) error {
+	themes := make(map[string]*Theme)
+	if err := addBuiltins(themes); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, dir := range append(Dirs(), extraDirs...) {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, e := range entries {
+			// Directories without a manifest, such as this package's own builtin/, aren't themes
+			sub := filepath.Join(dir, e.Name())
+			if _, err := os.Stat(filepath.Join(sub, ManifestFile)); !e.IsDir() || err != nil {
+				continue
+			}
+			t, err := Open(sub)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			themes[t.id] = t
+		}
+	}
+
+	registry = themes
+	return errors.Join(errs...)
+}
+
+// Open reads and checks the theme in dir; its ID is the directory's name
+func Open(dir string) (*Theme, error) {
+	fsys := os.DirFS(dir)
+	raw, err := fs.ReadFile(fsys, ManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %v", dir, err)
+	}
+	t, err := parse(raw, filepath.Base(dir))
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %v", dir, err)
+	}
+	t.source, t.fsys = dir, fsys
+	if err := t.checkFiles(); err != nil {
+		return nil, fmt.Errorf("theme %s: %v", dir, err)
+	}
+	return t, nil
+}
+
+// addBuiltins registers the embedded themes
+func addBuiltins(themes map[string]*Theme) error {
+	entries, err := builtinManifests.ReadDir("builtin")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		raw, err := builtinManifests.ReadFile(path.Join("builtin", e.Name()))
+		if err != nil {
+			return err
+		}
+		t, err := parse(raw, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			return fmt.Errorf("builtin theme %s: %v", e.Name(), err)
+		}
+		themes[t.id] = t
+	}
+	return nil
+}
+
+// parse decodes a theme manifest strictly and checks its values
+func parse(raw []byte, id string) (*Theme, error) {
+	var t Theme
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", ManifestFile, err)
+	}
+	t.id = id
+	if t.Name == "" {
+		t.Name = id
+	}
+
+	var errs []error
+	if t.Tint != "" {
+		if _, err := config.ParseColor(t.Tint); err != nil {
+			errs = append(errs, fmt.Errorf("tint: %v", err))
+		}
+	}
+	for brickType := range t.Bricks {
+		if _, ok := config.LookupBrickType(brickType); !ok {
+			errs = append(errs, fmt.Errorf("bricks: unknown brick type %q", brickType))
+		}
+	}
+	return &t, errors.Join(errs...)
+}
+
+// checkFiles reports files the theme names that aren't in its directory
+func (t *Theme) checkFiles() error {
+	var errs []error
+	for _, name := range t.Files() {
+		if _, err := fs.Stat(t.fsys, name); err != nil {
+			errs = append(errs, fmt.Errorf("file %q not found", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Lookup returns the theme with an ID, reporting whether there is one
+func Lookup(id string) (*Theme, bool) {
+	t, ok := registry[id]
+	return t, ok
+}
+
+// Get returns the theme with an ID, or the default theme for "" and unknown IDs
+func Get(id string) *Theme {
+	if t, ok := registry[id]; ok {
+		return t
+	}
+	return registry[DefaultID]
+}
+
+// IDs returns the IDs of every registered theme in sorted order
+func IDs() []string {
+	ids := make([]string, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ID returns the theme's registry ID
+func (t *Theme) ID() string {
+	return t.id
+}
+
+// Source returns the theme's directory, or "" for builtin themes
+func (t *Theme) Source() string {
+	return t.source
+}
+
+// Files returns every file the theme names, sorted
+func (t *Theme) Files() []string {
+	var files []string
+	for _, name := range []string{t.Background, t.Paddle, t.Music} {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	for _, name := range t.Bricks {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// TintColor returns the background tint, reporting whether the theme has one
+func (t *Theme) TintColor() (color.RGBA, bool) {
+	if t.Tint == "" {
+		return color.RGBA{}, false
+	}
+	c, err := config.ParseColor(t.Tint)
+	return c, err == nil
+}
+
+// ReadFile reads a file of the theme
+func (t *Theme) ReadFile(name string) ([]byte, error) {
+	fsys := t.fsys
+	if fsys == nil {
+		fsys = builtinFiles
+	}
+	if fsys == nil {
+		return nil, fmt.Errorf("theme %s: builtin theme files are not available", t.id)
+	}
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %v", t.id, err)
+	}
+	return data, nil
+}
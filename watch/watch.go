@@ -0,0 +1,80 @@
+// Package watch detects changed files by polling their modification times. It
+// is meant for development hot reload, where a short delay is fine and polling
+// avoids platform-specific file notification APIs.
+package watch
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileState is what a poll remembers about a file
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls a set of directory trees for added, changed and removed files
+type Watcher struct {
+	roots []string
+	files map[string]fileState
+}
+
+// New creates a watcher over the given directory trees, recording their current
+// state so only later changes are reported. Missing directories are watched in
+// case they appear.
+func New(roots ...string) *Watcher {
+	w := &Watcher{roots: roots}
+	w.files = w.scan()
+	return w
+}
+
+// Roots returns the watched directories
+func (w *Watcher) Roots() []string {
+	return w.roots
+}
+
+// Poll rescans the watched trees and returns the sorted paths of files added,
+// modified or removed since the previous poll.
+func (w *Watcher) Poll() []string {
+	current := w.scan()
+	var changed []string
+	for path, st := range current {
+		if prev, ok := w.files[path]; !ok || prev != st {
+			changed = append(changed, path)
+		}
+	}
+	for path := range w.files {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	w.files = current
+	sort.Strings(changed)
+	return changed
+}
+
+// Under reports whether path lies inside the directory root
+func Under(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func (w *Watcher) scan() map[string]fileState {
+	files := make(map[string]fileState)
+	for _, root := range w.roots {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil // unreadable or missing entries are simply not watched
+			}
+			if info, err := d.Info(); err == nil {
+				files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+	}
+	return files
+}